@@ -259,6 +259,7 @@ export default function ApiShowcase() {
                           method={api.method}
                           path={api.path}
                           baseUrl="https://api.example.com"
+                          direct
                           requestBody={api.requestBody?.example ? JSON.stringify(api.requestBody.example, null, 2) : undefined}
                         />
                       </div>
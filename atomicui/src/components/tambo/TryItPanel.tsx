@@ -1,12 +1,15 @@
 "use client";
 
 import { useState } from "react";
+import { appDuration, proxiedRequest } from "../../lib/proxy";
 
 interface TryItPanelProps {
   method?: string;
   path?: string;
   baseUrl?: string;
   requestBody?: string;
+  /** Call the API straight from the browser instead of through the AtomicDocs proxy. */
+  direct?: boolean;
 }
 
 export default function TryItPanel({
@@ -14,6 +17,7 @@ export default function TryItPanel({
   path = "/",
   baseUrl = "",
   requestBody = "{}",
+  direct = false,
 }: TryItPanelProps) {
   const [body, setBody] = useState(requestBody);
   const [response, setResponse] = useState<string | null>(null);
@@ -41,15 +45,20 @@ export default function TryItPanel({
           return;
         }
       }
-      const res = await fetch(url, opts);
+      const request = direct ? { url, init: opts } : proxiedRequest(baseUrl, path, opts);
+      const started = performance.now();
+      const res = await fetch(request.url, request.init);
       const text = await res.text();
+      const total = Math.round(performance.now() - started);
       let parsed: string;
       try {
         parsed = JSON.stringify(JSON.parse(text), null, 2);
       } catch {
         parsed = text;
       }
-      setResponse(`${res.status} ${res.statusText}\n\n${parsed}`);
+      const app = direct ? null : appDuration(res);
+      const timing = app === null ? `${total} ms` : `${total} ms, app ${app} ms`;
+      setResponse(`${res.status} ${res.statusText} · ${timing}\n\n${parsed}`);
     } catch (err) {
       setError(err instanceof Error ? err.message : "Request failed");
     } finally {
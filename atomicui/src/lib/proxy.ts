@@ -0,0 +1,41 @@
+/**
+ * Try-it requests go through the AtomicDocs server's proxy
+ * (/api/v1/proxy/{app}) so they work whatever the API's CORS policy is.
+ * The proxy only forwards to servers the app registered.
+ */
+
+export const TARGET_HEADER = "X-AtomicDocs-Target";
+
+const env = typeof import.meta !== "undefined" ? import.meta.env : undefined;
+
+// Go AtomicDocs server; the spec URL may point at it or at the app's /docs/json.
+const serverUrl = String(env?.VITE_ATOMICDOCS_URL ?? "http://localhost:6174").replace(/\/$/, "");
+const specUrl = String(env?.VITE_OPENAPI_URL ?? `${serverUrl}/docs`);
+
+/** The app whose API is documented: VITE_ATOMICDOCS_APP, the spec URL's ?port=, or the port the spec is served from. */
+function appId(): string {
+  if (env?.VITE_ATOMICDOCS_APP) return String(env.VITE_ATOMICDOCS_APP);
+  try {
+    const u = new URL(specUrl);
+    const fromQuery = u.searchParams.get("port") ?? u.searchParams.get("app");
+    if (fromQuery) return fromQuery;
+    if (u.origin !== new URL(serverUrl).origin && u.port) return u.port;
+  } catch {
+    // fall through to the default app
+  }
+  return "3000";
+}
+
+/** Rewrites a request for baseUrl + path into one through the proxy. Without a baseUrl the app's first server is used. */
+export function proxiedRequest(baseUrl: string, path: string, init: RequestInit): { url: string; init: RequestInit } {
+  const headers = new Headers(init.headers);
+  if (baseUrl) headers.set(TARGET_HEADER, baseUrl.replace(/\/$/, ""));
+  const url = `${serverUrl}/api/v1/proxy/${encodeURIComponent(appId())}${path.startsWith("/") ? path : `/${path}`}`;
+  return { url, init: { ...init, headers } };
+}
+
+/** How long the app took to answer, from the proxy's Server-Timing header, in milliseconds. */
+export function appDuration(res: Response): number | null {
+  const match = res.headers.get("Server-Timing")?.match(/app;dur=([\d.]+)/);
+  return match ? Number(match[1]) : null;
+}
@@ -130,7 +130,10 @@ export const components: TamboComponent[] = [
     propsSchema: z.object({
       method: z.string().describe("HTTP method"),
       path: z.string().describe("API path"),
-      baseUrl: z.string().optional().describe("Base URL of the API (e.g. http://localhost:3000)"),
+      baseUrl: z
+        .string()
+        .optional()
+        .describe("Base URL of the API, one of the spec's servers (e.g. http://localhost:3000); defaults to the first server"),
       requestBody: z.string().optional().describe("Default request body JSON"),
     }),
   },
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/atomicdocs/internal/site"
+)
+
+// runBuild implements "atomicdocs build spec.json", which renders a spec
+// as a static HTML site or a single Markdown file. It exits with 0 on
+// success, 1 when rendering fails and 2 on usage or input errors.
+func runBuild(args []string) int {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	format := fs.String("format", "html", "output format: html or markdown")
+	out := fs.String("out", "", "output directory for html (default ./site), file for markdown (default standard output)")
+	templates := fs.String("templates", "", "directory of templates replacing the built-in ones")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: atomicdocs build [-format html|markdown] [-out path] [-templates dir] spec.json|URL")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	spec, err := readSpec(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	opts := site.Options{Templates: *templates}
+
+	switch *format {
+	case "html":
+		dir := *out
+		if dir == "" {
+			dir = "site"
+		}
+		if err := site.Build(spec, dir, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "markdown", "md":
+		w := os.Stdout
+		if *out != "" {
+			f, err := os.Create(*out)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := site.Markdown(spec, w, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	return 0
+}
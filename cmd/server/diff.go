@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/atomicdocs/internal/diff"
+)
+
+// runDiff implements "atomicdocs diff old.json new.json". It exits with 0
+// when there are no breaking changes, 1 when there are and 2 on usage or
+// input errors.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format: text, json or markdown")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: atomicdocs diff [-format text|json|markdown] old.json new.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	old, err := readSpec(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	new, err := readSpec(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	report := diff.Compare(old, new)
+
+	switch *format {
+	case "json":
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	case "markdown", "md":
+		fmt.Print(report.Markdown())
+	case "text":
+		fmt.Print(report.Text())
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	if report.HasBreaking() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/atomicdocs/internal/export"
+)
+
+// runExport implements "atomicdocs export spec.json", which writes a spec
+// as JSON or YAML, or converts it into the format of another API tool. The
+// spec may also be fetched from a running server. It exits with 0 on
+// success and 2 on usage or input errors.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "postman", "output format: json, yaml, postman, insomnia or har")
+	app := addAppFlags(fs)
+	out := fs.String("o", "", "write to this file instead of standard output")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: atomicdocs export [-format json|yaml|postman|insomnia|har] [-app id] [-server url] [-o file] [spec.json|URL]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	source, ok := app.source(fs.Args())
+	if !ok {
+		fs.Usage()
+		return 2
+	}
+
+	spec, err := readSpec(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var result interface{}
+	switch *format {
+	case "json", "yaml":
+		result = spec
+	case "postman":
+		result = export.Postman(spec)
+	case "insomnia":
+		result = export.Insomnia(spec)
+	case "har":
+		result = export.HARFile(spec)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	var data []byte
+	if *format == "yaml" {
+		data, _ = export.YAML(result)
+	} else {
+		data, _ = json.MarshalIndent(result, "", "  ")
+		data = append(data, '\n')
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/atomicdocs/internal/codegen"
+)
+
+// runGen implements "atomicdocs gen go" and "atomicdocs gen ts", which
+// generate a typed client from a spec file or URL, or from the spec of an
+// app registered with a running server. It exits with 0 on success, 1 when generation fails and
+// 2 on usage or input errors.
+func runGen(args []string) int {
+	usage := "Usage: atomicdocs gen go|ts [-app id] [-server url] [-pkg name] [-types] [-o file] [spec.json|URL]"
+	if len(args) == 0 || (args[0] != "go" && args[0] != "ts") {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	language := args[0]
+
+	fs := flag.NewFlagSet("gen "+language, flag.ContinueOnError)
+	app := addAppFlags(fs)
+	pkg := fs.String("pkg", "client", "package name of the generated Go code")
+	typesOnly := fs.Bool("types", false, "write only the TypeScript declarations, as a .d.ts file")
+	out := fs.String("o", "", "write to this file instead of standard output")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	source, ok := app.source(fs.Args())
+	if !ok || (*typesOnly && language != "ts") {
+		fs.Usage()
+		return 2
+	}
+	spec, err := readSpec(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var code []byte
+	switch {
+	case language == "ts" && *typesOnly:
+		code = codegen.TypeScriptTypes(spec)
+	case language == "ts":
+		code = codegen.TypeScript(spec)
+	default:
+		if code, err = codegen.Go(spec, *pkg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if *out == "" {
+		os.Stdout.Write(code)
+		return 0
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/registry"
+)
+
+// runInspect implements "atomicdocs inspect [app]", which lists the apps
+// registered with a running server, or the routes of one of them. It exits
+// with 0 on success and 2 on usage errors or when the server cannot be
+// read.
+func runInspect(args []string) int {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	server := fs.String("server", "http://localhost:6174", "server to inspect")
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: atomicdocs inspect [-server url] [-format text|json] [app]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		return inspectApps(*server, *format)
+	}
+	return inspectRoutes(*server, fs.Arg(0), *format)
+}
+
+func inspectApps(server, format string) int {
+	data, err := readSource(serverURL(server, "/api/v1/apps"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var resp struct {
+		Apps []registry.AppSummary `json:"apps"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse the app list: %v\n", err)
+		return 2
+	}
+
+	if format == "json" {
+		out, _ := json.MarshalIndent(resp.Apps, "", "  ")
+		fmt.Println(string(out))
+		return 0
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APP\tVERSION\tROUTES\tREGISTERED\tGIT SHA")
+	for _, app := range resp.Apps {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", app.App, app.Current.Number, app.Current.RouteCount, app.Current.Timestamp.Local().Format(time.RFC3339), app.Current.GitSHA)
+	}
+	w.Flush()
+	return 0
+}
+
+// route is an operation as inspect lists it.
+type route struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationId,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+func inspectRoutes(server, app, format string) int {
+	// The spec of an app that is not registered is empty rather than
+	// missing, so ask for the app first.
+	base := serverURL(server, "/api/v1/apps/"+url.PathEscape(app))
+	if _, err := readSource(base); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	spec, err := readSpec(base + "/spec")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	routes := []route{}
+	for _, path := range sortedPaths(spec) {
+		item := spec.Paths[path]
+		for _, method := range openapi.Methods {
+			if op := item.Operation(method); op != nil {
+				routes = append(routes, route{Method: method, Path: path, OperationID: op.OperationID, Summary: op.Summary, Tags: op.Tags})
+			}
+		}
+	}
+
+	if format == "json" {
+		out, _ := json.MarshalIndent(routes, "", "  ")
+		fmt.Println(string(out))
+		return 0
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tOPERATION\tSUMMARY")
+	for _, r := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Method, r.Path, r.OperationID, r.Summary)
+	}
+	w.Flush()
+	return 0
+}
+
+func sortedPaths(spec *openapi.Spec) []string {
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
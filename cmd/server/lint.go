@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/yourusername/atomicdocs/internal/lint"
+)
+
+// defaultLintConfig is read by "atomicdocs lint" when it exists and no
+// -config is given.
+const defaultLintConfig = "atomicdocs-lint.json"
+
+// runLint implements "atomicdocs lint spec.json", which checks a spec
+// against the API conventions. It exits with 0 when no rule reports a
+// finding as severe as -fail-on, 1 when one does and 2 on usage or input
+// errors.
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format: text or json")
+	configPath := fs.String("config", "", "lint config file (default "+defaultLintConfig+" when it exists)")
+	failOn := fs.String("fail-on", "error", "exit with 1 on findings of this severity or worse: error or warning")
+	listRules := fs.Bool("rules", false, "list the rules and their severities, then exit")
+	app := addAppFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: atomicdocs lint [-config file] [-fail-on error|warning] [-format text|json] [-app id] [-server url] [spec.json|URL]")
+		fmt.Fprintln(fs.Output(), "       atomicdocs lint -rules [-config file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *failOn != string(lint.Error) && *failOn != string(lint.Warning) {
+		fmt.Fprintf(os.Stderr, "unknown -fail-on %q: use error or warning\n", *failOn)
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	cfg, err := loadLintConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *listRules {
+		printRules(cfg)
+		return 0
+	}
+
+	source, ok := app.source(fs.Args())
+	if !ok {
+		fs.Usage()
+		return 2
+	}
+	spec, err := readSpec(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	report, err := lint.Run(spec, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if *format == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(report.Text())
+	}
+	if report.Failed(lint.Severity(*failOn)) {
+		return 1
+	}
+	return 0
+}
+
+// loadLintConfig reads the lint config at path, or the default config when
+// path is empty. Without either, the built-in rules run at their defaults.
+func loadLintConfig(path string) (lint.Config, error) {
+	if path == "" {
+		if _, err := os.Stat(defaultLintConfig); err != nil {
+			return lint.Config{}, nil
+		}
+		path = defaultLintConfig
+	}
+	return lint.LoadConfig(path)
+}
+
+// printRules lists every rule with its severity under cfg.
+func printRules(cfg lint.Config) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tSEVERITY\tDESCRIPTION")
+	for _, rule := range cfg.All() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", rule.ID, rule.SeverityIn(cfg), rule.Description)
+	}
+	w.Flush()
+}
@@ -2,42 +2,60 @@ package main
 
 import (
 	"fmt"
-	"log"
-	
-	"github.com/valyala/fasthttp"
-	"github.com/yourusername/atomicdocs/internal/middleware"
-	"github.com/yourusername/atomicdocs/internal/registry"
+	"os"
+	"strings"
 )
 
+// Set at release time with -ldflags "-X main.version=... -X main.commit=... -X main.date=...".
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// commands are the subcommands of the CLI. Running it without one, or
+// with flags only, serves as before.
+var commands = map[string]func(args []string) int{
+	"serve":    runServe,
+	"export":   runExport,
+	"diff":     runDiff,
+	"lint":     runLint,
+	"validate": runValidate,
+	"inspect":  runInspect,
+	"build":    runBuild,
+	"gen":      runGen,
+}
+
+const usage = `Usage: atomicdocs <command> [flags] [args]
+
+Commands:
+  serve      run the documentation server (the default)
+  export     write a spec as JSON, YAML, Postman, Insomnia or HAR
+  diff       compare two specs and report breaking changes
+  lint       check a spec against the API conventions
+  validate   check that a spec is a valid OpenAPI document
+  inspect    list the apps and routes of a running server
+  build      render a spec as a static HTML site or Markdown
+  gen        generate a Go or TypeScript client
+
+Run "atomicdocs <command> -h" for the flags of a command. Commands exit
+with 0 on success, 1 when they find problems and 2 on usage or input
+errors.
+`
+
 func main() {
-	reg := registry.New()
-	handler := middleware.NewHandler(reg)
-	
-	requestHandler := func(ctx *fasthttp.RequestCtx) {
-		path := string(ctx.Path())
-		method := string(ctx.Method())
-		
-		fmt.Printf("Request: %s %s\n", method, path)
-		
-		// Set JSON content type for all responses
-		ctx.SetContentType("application/json")
-		
-		switch path {
-		case "/api/register":
-			handler.RegisterRoutes(ctx)
-		case "/docs":
-			handler.ServeUI(ctx)
-		case "/docs/json":
-			handler.GetSpec(ctx)
-		default:
-			ctx.SetStatusCode(fasthttp.StatusNotFound)
-			ctx.SetBody([]byte(`{"error": "Not found"}`))
-		}
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runServe(os.Args[1:]))
 	}
-	
-	fmt.Println("AtomicDocs server starting on :6174")
-	
-	if err := fasthttp.ListenAndServe(":6174", requestHandler); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	switch name := os.Args[1]; name {
+	case "help":
+		fmt.Print(usage)
+	default:
+		run, ok := commands[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+			os.Exit(2)
+		}
+		os.Exit(run(os.Args[2:]))
 	}
-}
\ No newline at end of file
+}
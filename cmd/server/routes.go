@@ -0,0 +1,77 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/yourusername/atomicdocs/internal/config"
+	"github.com/yourusername/atomicdocs/internal/middleware"
+	"github.com/yourusername/atomicdocs/internal/router"
+)
+
+// newRouter wires the server endpoints. The unversioned paths are kept for
+// the framework adapters and older clients.
+func newRouter(handler *middleware.Handler, cfg *config.Config, build middleware.BuildInfo, logger *slog.Logger) *router.Router {
+	r := router.New()
+	r.Use(
+		middleware.RequestID,
+		handler.Instrument,
+		middleware.Recover(logger),
+		middleware.Logger(logger),
+		middleware.CORS(cfg.CORS),
+	)
+
+	viewer := handler.RequireViewer
+
+	r.GET("/healthz", handler.Healthz)
+	r.GET("/readyz", handler.Readyz)
+	r.GET("/version", middleware.Version(build))
+	r.GET("/metrics", handler.ServeMetrics)
+
+	r.GET("/docs", handler.ServeUI, viewer)
+	r.GET("/docs/json", handler.GetSpec, viewer)
+	r.GET("/docs/postman.json", handler.GetPostman, viewer)
+	r.GET("/docs/insomnia.json", handler.GetInsomnia, viewer)
+	r.GET("/docs/requests.har", handler.GetHAR, viewer)
+	r.GET("/docs/client.ts", handler.GetTypeScriptClient, viewer)
+	r.GET("/docs/client.d.ts", handler.GetTypeScriptTypes, viewer)
+	r.GET("/docs/lint", handler.GetLint, viewer)
+	r.GET("/docs/diagnostics", handler.GetDiagnostics, viewer)
+
+	r.POST("/api/register", handler.RegisterRoutes)
+	r.GET("/api/diff", handler.GetDiff, viewer)
+	r.GET("/api/history", handler.GetHistory, viewer)
+	r.GET("/api/history/spec", handler.GetVersionSpec, viewer)
+	r.GET("/api/history/changelog", handler.GetChangelog, viewer)
+	r.GET("/api/events", handler.StreamEvents, handler.RequireStreamViewer)
+
+	// The mock and the try-it proxy answer every method an API may use.
+	mock := r.Group("/mock/{app}", viewer)
+	tryIt := r.Group("/api/v1/proxy/{app}", viewer)
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+		mock.Handle(method, "/{path...}", handler.ServeMock)
+		tryIt.Handle(method, "/{path...}", handler.ProxyRequest)
+	}
+
+	v1 := r.Group("/api/v1")
+	v1.GET("/health", handler.Healthz)
+	v1.POST("/register", handler.RegisterRoutes)
+	v1.GET("/apps", handler.ListApps)
+	v1.GET("/apps/{app}/events", handler.StreamEvents, handler.RequireStreamViewer)
+
+	app := v1.Group("/apps/{app}", viewer)
+	app.GET("", handler.GetApp)
+	app.GET("/spec", handler.GetSpec)
+	app.GET("/postman.json", handler.GetPostman)
+	app.GET("/insomnia.json", handler.GetInsomnia)
+	app.GET("/requests.har", handler.GetHAR)
+	app.GET("/client.ts", handler.GetTypeScriptClient)
+	app.GET("/client.d.ts", handler.GetTypeScriptTypes)
+	app.GET("/lint", handler.GetLint)
+	app.GET("/diagnostics", handler.GetDiagnostics)
+	app.GET("/diff", handler.GetDiff)
+	app.GET("/versions", handler.GetHistory)
+	app.GET("/versions/{version}/spec", handler.GetVersionSpec)
+	app.GET("/changelog", handler.GetChangelog)
+
+	return r
+}
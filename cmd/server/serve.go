@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/config"
+	"github.com/yourusername/atomicdocs/internal/logging"
+	"github.com/yourusername/atomicdocs/internal/middleware"
+	"github.com/yourusername/atomicdocs/internal/registry"
+)
+
+// runServe implements "atomicdocs serve", which runs the documentation
+// server until it fails. It exits with 1 when the config is invalid or the
+// server cannot start, and 2 on usage errors.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to a JSON config file")
+	addr := fs.String("addr", "", "listen address (overrides config)")
+	showVersion := fs.Bool("version", false, "print version information and exit")
+	logLevel := fs.String("log-level", "", "log level: debug, info, warn or error (overrides config)")
+	logFormat := fs.String("log-format", "", "log format: text or json (overrides config)")
+	debugMode := fs.Bool("debug", false, "log at debug level and capture registrations")
+	captureDir := fs.String("capture-dir", "", "write every registration received to this directory (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: atomicdocs [serve] [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	build := middleware.BuildInfo{Version: version, Commit: commit, BuildDate: date}.Complete()
+	if *showVersion {
+		fmt.Printf("atomicdocs %s (commit %s, built %s, %s)\n", build.Version, build.Commit, build.BuildDate, build.GoVersion)
+		return 0
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	if *addr != "" {
+		cfg.Addr = *addr
+	}
+	if *logLevel != "" {
+		cfg.Log.Level = *logLevel
+	}
+	if *debugMode {
+		cfg.Log.Level = "debug"
+		if cfg.Log.CaptureDir == "" {
+			cfg.Log.CaptureDir = filepath.Join(os.TempDir(), "atomicdocs")
+		}
+	}
+	if *logFormat != "" {
+		cfg.Log.Format = *logFormat
+	}
+	if *captureDir != "" {
+		cfg.Log.CaptureDir = *captureDir
+	}
+
+	logger, err := logging.New(cfg.Log, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid log config: %v\n", err)
+		return 1
+	}
+	slog.SetDefault(logger)
+
+	reg := registry.New(cfg.HistoryLimit)
+	handler := middleware.NewHandler(reg, cfg, logger)
+
+	r := newRouter(handler, cfg, build, logger)
+
+	logger.Info("AtomicDocs server starting", "addr", cfg.Addr, "version", build.Version)
+	if cfg.Log.CaptureDir != "" {
+		logger.Warn("capturing registrations", "dir", cfg.Log.CaptureDir)
+	}
+
+	if err := fasthttp.ListenAndServe(cfg.Addr, r.Handler()); err != nil {
+		logger.Error("failed to start server", "error", err)
+		return 1
+	}
+	return 0
+}
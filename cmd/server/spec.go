@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// tokenEnv names the environment variable holding the token sent to
+// servers that restrict who can view the docs.
+const tokenEnv = "ATOMICDOCS_TOKEN"
+
+// readSpec reads a JSON or YAML spec from a file, or fetches it when path
+// is an http(s) URL such as a running server's /docs/json.
+func readSpec(path string) (*openapi.Spec, error) {
+	data, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec openapi.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// readDocument reads a spec as readSpec does, and returns it as JSON.
+func readDocument(path string) ([]byte, error) {
+	data, err := readSource(path)
+	if err != nil {
+		return nil, err
+	}
+	if json.Valid(data) {
+		return data, nil
+	}
+	doc, err := openapi.ParseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return json.Marshal(doc)
+}
+
+// readSource reads a file, or fetches an http(s) URL with the token in
+// ATOMICDOCS_TOKEN when it is set.
+func readSource(path string) ([]byte, error) {
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, nil
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(path)
+	if token := os.Getenv(tokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if err := fasthttp.DoTimeout(req, resp, 30*time.Second); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if status := resp.StatusCode(); status != fasthttp.StatusOK {
+		return nil, fmt.Errorf("failed to read %s: status %d", path, status)
+	}
+	return append([]byte(nil), resp.Body()...), nil
+}
+
+// appFlags are the -app and -server flags of the commands that can read
+// the spec of an app registered with a running server instead of a file.
+type appFlags struct {
+	app    *string
+	server *string
+}
+
+func addAppFlags(fs *flag.FlagSet) appFlags {
+	return appFlags{
+		app:    fs.String("app", "", "app whose spec the server serves, instead of a spec file"),
+		server: fs.String("server", "http://localhost:6174", "server the app registered with"),
+	}
+}
+
+// source returns where to read the spec from: the app's spec on the
+// server, or the file or URL in args. It reports false unless exactly one
+// of them is given.
+func (f appFlags) source(args []string) (string, bool) {
+	switch {
+	case len(args) > 1 || (len(args) == 1) == (*f.app != ""):
+		return "", false
+	case *f.app != "":
+		return serverURL(*f.server, "/api/v1/apps/"+url.PathEscape(*f.app)+"/spec"), true
+	}
+	return args[0], true
+}
+
+// serverURL joins a server's address and a path.
+func serverURL(server, path string) string {
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+	return strings.TrimSuffix(server, "/") + path
+}
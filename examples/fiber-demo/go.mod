@@ -3,7 +3,7 @@ module fiber-demo
 go 1.23.3
 
 require (
-	github.com/gofiber/fiber/v2 v2.52.10
+	github.com/gofiber/fiber/v2 v2.52.11
 	github.com/yourusername/atomicdocs/fiber v0.0.0
 )
 
@@ -20,5 +20,8 @@ require (
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasthttp v1.51.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
+	github.com/yourusername/atomicdocs v0.0.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 )
+
+replace github.com/yourusername/atomicdocs => ../..
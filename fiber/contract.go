@@ -0,0 +1,276 @@
+package atomicdocs
+
+import (
+	"log/slog"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/yourusername/atomicdocs/validate"
+)
+
+// ContractReportPath is where Contract serves its report.
+const ContractReportPath = "/docs/contract-report"
+
+const (
+	// ContractLog logs responses that break the documented contract.
+	ContractLog = "log"
+	// ContractFail also replaces them with a 500 problem+json error, so
+	// tests notice.
+	ContractFail = "fail"
+)
+
+// ContractConfig configures Contract.
+type ContractConfig struct {
+	// Mode is ContractLog (the default) or ContractFail.
+	Mode string
+
+	// Next skips checking for a request when it returns true.
+	Next func(c *fiber.Ctx) bool
+
+	// Logger receives violations at warn level. Defaults to slog.Default().
+	Logger *slog.Logger
+
+	// OnViolation, when set, is called for every violation, e.g. to fail a
+	// test.
+	OnViolation func(c *fiber.Ctx, violation ContractViolation)
+
+	// Samples is how many recent violations the report keeps per route.
+	// Defaults to 10.
+	Samples int
+}
+
+// ContractViolation is one response that did not match its documentation.
+type ContractViolation struct {
+	Method      string       `json:"method"`
+	Path        string       `json:"path"`
+	URL         string       `json:"url"`
+	Status      int          `json:"status"`
+	ContentType string       `json:"contentType,omitempty"`
+	Time        time.Time    `json:"time"`
+	Errors      []FieldError `json:"errors"`
+}
+
+// ContractReport summarises the responses checked so far.
+type ContractReport struct {
+	Checked    int             `json:"checked"`
+	Violations int             `json:"violations"`
+	Routes     []RouteContract `json:"routes"`
+}
+
+// RouteContract is the report of one documented route.
+type RouteContract struct {
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Checked    int                 `json:"checked"`
+	Violations int                 `json:"violations"`
+	Statuses   map[string]int      `json:"statuses"`
+	Recent     []ContractViolation `json:"recent,omitempty"`
+}
+
+type contractState struct {
+	mu     sync.Mutex
+	routes map[string]*RouteContract
+}
+
+// Contract checks every response against the documentation of its route:
+// the status code must be documented, the content type must be one the
+// status documents, and JSON bodies must match the documented schema. It is
+// meant for development and test runs, not production traffic.
+//
+// Violations are logged, or in ContractFail mode turned into 500 errors,
+// and collected in a JSON report served at /docs/contract-report.
+func Contract(config ...ContractConfig) fiber.Handler {
+	cfg := ContractConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = ContractLog
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.Samples <= 0 {
+		cfg.Samples = 10
+	}
+	state := &contractState{routes: make(map[string]*RouteContract)}
+
+	return func(c *fiber.Ctx) error {
+		if c.Path() == ContractReportPath && c.Method() == fiber.MethodGet {
+			return c.JSON(state.report())
+		}
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		route, _ := routeTableFor(c.App()).match(c.Method(), c.Path())
+		if route == nil {
+			return c.Next()
+		}
+
+		// Let errors go through the app's error handler first, so the
+		// response that is checked is the one the client gets.
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				return handlerErr
+			}
+		}
+
+		status := c.Response().StatusCode()
+		contentType := string(c.Response().Header.ContentType())
+		errs := checkResponse(route, status, contentType, c.Response().Body())
+
+		var violation *ContractViolation
+		if len(errs) > 0 {
+			violation = &ContractViolation{
+				Method:      route.Method,
+				Path:        route.Path,
+				URL:         utils.CopyString(c.OriginalURL()),
+				Status:      status,
+				ContentType: contentType,
+				Time:        time.Now().UTC(),
+				Errors:      errs,
+			}
+		}
+		state.record(route, status, cfg.Samples, violation)
+		if violation == nil {
+			return nil
+		}
+
+		cfg.Logger.Warn("atomicdocs: response does not match the documented contract",
+			"method", route.Method, "path", route.Path, "status", status, "errors", errs)
+		if cfg.OnViolation != nil {
+			cfg.OnViolation(c, *violation)
+		}
+
+		if cfg.Mode == ContractFail {
+			c.Response().ResetBody()
+			return sendProblem(c, Problem{
+				Type:     "about:blank",
+				Title:    "Internal Server Error",
+				Status:   fiber.StatusInternalServerError,
+				Detail:   "The response does not match the documented API.",
+				Instance: c.OriginalURL(),
+				Errors:   errs,
+			})
+		}
+		return nil
+	}
+}
+
+// checkResponse compares a response with the documented responses of its
+// route.
+func checkResponse(route *RouteInfo, status int, contentType string, body []byte) []FieldError {
+	response, ok := documentedResponse(route.Responses, status)
+	if !ok {
+		return []FieldError{{In: "status", Message: "status " + strconv.Itoa(status) + " is not documented"}}
+	}
+	if len(response.Content) == 0 || len(body) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := documentedMedia(response.Content, mediaType)
+	if !ok {
+		documentedTypes := make([]string, 0, len(response.Content))
+		for name := range response.Content {
+			documentedTypes = append(documentedTypes, name)
+		}
+		sort.Strings(documentedTypes)
+		return []FieldError{{
+			In:      "header",
+			Name:    fiber.HeaderContentType,
+			Message: "content type " + strconv.Quote(mediaType) + " is not documented; expected " + strings.Join(documentedTypes, ", "),
+		}}
+	}
+
+	if mediaType != fiber.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+	value, err := validate.DecodeJSON(body)
+	if err != nil {
+		return []FieldError{{In: "body", Message: "is not valid JSON: " + err.Error()}}
+	}
+	return validate.Value("body", media.Schema, value, nil)
+}
+
+// documentedResponse finds the response documented for a status: the exact
+// code, then its class ("4XX"), then "default".
+func documentedResponse(responses map[string]Response, status int) (Response, bool) {
+	code := strconv.Itoa(status)
+	if response, ok := responses[code]; ok {
+		return response, true
+	}
+	for key, response := range responses {
+		if len(key) == 3 && strings.EqualFold(key[1:], "xx") && key[0] == code[0] {
+			return response, true
+		}
+	}
+	response, ok := responses["default"]
+	return response, ok
+}
+
+func documentedMedia(content map[string]MediaTypeObject, mediaType string) (MediaTypeObject, bool) {
+	if media, ok := content[mediaType]; ok {
+		return media, true
+	}
+	if major, _, ok := strings.Cut(mediaType, "/"); ok {
+		if media, ok := content[major+"/*"]; ok {
+			return media, true
+		}
+	}
+	media, ok := content["*/*"]
+	return media, ok
+}
+
+func (s *contractState) record(route *RouteInfo, status, samples int, violation *ContractViolation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := route.Method + " " + route.Path
+	rc, ok := s.routes[key]
+	if !ok {
+		rc = &RouteContract{Method: route.Method, Path: route.Path, Statuses: make(map[string]int)}
+		s.routes[key] = rc
+	}
+	rc.Checked++
+	rc.Statuses[strconv.Itoa(status)]++
+	if violation != nil {
+		rc.Violations++
+		rc.Recent = append(rc.Recent, *violation)
+		if len(rc.Recent) > samples {
+			rc.Recent = rc.Recent[len(rc.Recent)-samples:]
+		}
+	}
+}
+
+func (s *contractState) report() ContractReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := ContractReport{Routes: []RouteContract{}}
+	for _, rc := range s.routes {
+		copied := *rc
+		copied.Statuses = make(map[string]int, len(rc.Statuses))
+		for status, n := range rc.Statuses {
+			copied.Statuses[status] = n
+		}
+		copied.Recent = append([]ContractViolation(nil), rc.Recent...)
+		report.Routes = append(report.Routes, copied)
+		report.Checked += rc.Checked
+		report.Violations += rc.Violations
+	}
+	sort.Slice(report.Routes, func(i, j int) bool {
+		if report.Routes[i].Path != report.Routes[j].Path {
+			return report.Routes[i].Path < report.Routes[j].Path
+		}
+		return report.Routes[i].Method < report.Routes[j].Method
+	})
+	return report
+}
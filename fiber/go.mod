@@ -2,7 +2,10 @@ module github.com/yourusername/atomicdocs/fiber
 
 go 1.23.3
 
-require github.com/gofiber/fiber/v2 v2.52.10
+require (
+	github.com/gofiber/fiber/v2 v2.52.11
+	github.com/yourusername/atomicdocs v0.0.0
+)
 
 require (
 	github.com/andybalholm/brotli v1.1.0 // indirect
@@ -17,3 +20,5 @@ require (
 	github.com/valyala/tcplisten v1.0.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 )
+
+replace github.com/yourusername/atomicdocs => ../
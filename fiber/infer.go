@@ -0,0 +1,241 @@
+package atomicdocs
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/validate"
+)
+
+const (
+	// maxTrackedValues bounds the distinct values remembered per field;
+	// fields with more are not enum candidates.
+	maxTrackedValues = 20
+	// maxEnumValues is the most values an inferred enum may have.
+	maxEnumValues = 8
+	// minEnumSamples is how often, on average, each value must have been
+	// seen before a field is documented as an enum.
+	minEnumSamples = 3
+	// maxProperties and maxArrayItems bound the memory one route can use.
+	maxProperties = 256
+	maxArrayItems = 50
+)
+
+// redactedExample replaces examples of fields that look like secrets.
+const redactedExample = "[REDACTED]"
+
+// shape accumulates what was observed of one JSON value across samples.
+type shape struct {
+	seen  int
+	nulls int
+	types map[string]int
+
+	formats  map[string]int
+	values   map[string]int
+	tooMany  bool
+	redacted bool
+	example  interface{}
+
+	objects int
+	props   map[string]*shape
+	items   *shape
+}
+
+func newShape() *shape {
+	return &shape{types: make(map[string]int), formats: make(map[string]int), values: make(map[string]int)}
+}
+
+// observe merges one value into the shape. redacted marks values that must
+// not be kept as examples or enum candidates; redact decides that for
+// object properties by name.
+func (s *shape) observe(value interface{}, redacted bool, redact func(name string) bool) {
+	if value == nil {
+		s.nulls++
+		return
+	}
+	s.seen++
+	if redacted {
+		s.redacted = true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.types["object"]++
+		s.objects++
+		if s.props == nil {
+			s.props = make(map[string]*shape)
+		}
+		for name, field := range v {
+			prop, ok := s.props[name]
+			if !ok {
+				if len(s.props) >= maxProperties {
+					continue
+				}
+				prop = newShape()
+				s.props[name] = prop
+			}
+			prop.observe(field, redacted || redact(name), redact)
+		}
+	case []interface{}:
+		s.types["array"]++
+		if s.items == nil {
+			s.items = newShape()
+		}
+		for i, item := range v {
+			if i >= maxArrayItems {
+				break
+			}
+			s.items.observe(item, redacted, redact)
+		}
+	case string:
+		s.types["string"]++
+		s.formats[detectFormat(v)]++
+		s.track(v, v, redacted)
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(v.String(), ".eE") {
+			s.types["integer"]++
+		} else {
+			s.types["number"]++
+		}
+		s.track(v.String(), v, redacted)
+	case bool:
+		s.types["boolean"]++
+		s.track(strconv.FormatBool(v), v, redacted)
+	}
+}
+
+func (s *shape) track(key string, value interface{}, redacted bool) {
+	if redacted {
+		s.tooMany = true
+		s.values = nil
+		return
+	}
+	if s.example == nil {
+		s.example = value
+	}
+	if s.tooMany {
+		return
+	}
+	s.values[key]++
+	if len(s.values) > maxTrackedValues {
+		s.tooMany = true
+		s.values = nil
+	}
+}
+
+// schema documents the shape. Properties present in every observed object
+// are required; strings that always had the same format get it, and strings
+// drawn from a few repeating values become enums.
+func (s *shape) schema() Schema {
+	schema := Schema{Nullable: s.nulls > 0}
+	schema.Type = s.dominantType()
+
+	switch schema.Type {
+	case "object":
+		schema.Properties = make(map[string]Schema, len(s.props))
+		for name, prop := range s.props {
+			schema.Properties[name] = prop.schema()
+			if prop.seen+prop.nulls == s.objects {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		sort.Strings(schema.Required)
+	case "array":
+		if s.items != nil && s.items.seen+s.items.nulls > 0 {
+			items := s.items.schema()
+			schema.Items = &items
+		}
+	case "string":
+		if len(s.formats) == 1 {
+			for format := range s.formats {
+				schema.Format = format
+			}
+		}
+		if schema.Format == "" && s.enumCandidate() {
+			for value := range s.values {
+				schema.Enum = append(schema.Enum, value)
+			}
+			sort.Strings(schema.Enum)
+		}
+		schema.Example = s.example
+	case "integer", "number", "boolean":
+		schema.Example = s.example
+	}
+
+	if s.redacted && schema.Type == "string" {
+		schema.Example = redactedExample
+	} else if s.redacted {
+		schema.Example = nil
+	}
+	return schema
+}
+
+// dominantType is the JSON type seen most often; integers and decimals
+// together make a number.
+func (s *shape) dominantType() string {
+	if s.types["integer"] > 0 && s.types["number"] > 0 {
+		merged := make(map[string]int, len(s.types))
+		for typ, n := range s.types {
+			merged[typ] = n
+		}
+		merged["number"] += merged["integer"]
+		delete(merged, "integer")
+		return mostFrequent(merged)
+	}
+	return mostFrequent(s.types)
+}
+
+func mostFrequent(counts map[string]int) string {
+	best, bestCount := "", 0
+	for key, n := range counts {
+		if n > bestCount || (n == bestCount && key < best) {
+			best, bestCount = key, n
+		}
+	}
+	return best
+}
+
+func (s *shape) enumCandidate() bool {
+	if s.tooMany || len(s.values) < 2 || len(s.values) > maxEnumValues {
+		return false
+	}
+	return s.seen >= minEnumSamples*len(s.values)
+}
+
+// detectFormat recognises the formats worth documenting. Plain strings
+// yield "".
+func detectFormat(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case validate.ValidFormat("uuid", s):
+		return "uuid"
+	case len(s) >= 20 && validate.ValidFormat("date-time", s):
+		return "date-time"
+	case len(s) == 10 && validate.ValidFormat("date", s):
+		return "date"
+	case strings.Contains(s, "@") && validate.ValidFormat("email", s):
+		return "email"
+	case strings.Contains(s, "://") && validate.ValidFormat("uri", s):
+		return "uri"
+	}
+	return ""
+}
+
+// parseParam turns a raw parameter value into the JSON value it most
+// likely stands for.
+func parseParam(raw string) interface{} {
+	if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return json.Number(raw)
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err == nil && !strings.ContainsAny(raw, "xXnN") {
+		return json.Number(raw)
+	}
+	if raw == "true" || raw == "false" {
+		return raw == "true"
+	}
+	return raw
+}
@@ -0,0 +1,446 @@
+package atomicdocs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/yourusername/atomicdocs/validate"
+)
+
+// ProvenanceTraffic marks schemas learned from observed traffic.
+const ProvenanceTraffic = "traffic"
+
+// Provenance records where a route's schemas came from when they were not
+// written by hand.
+type Provenance struct {
+	Source    string    `json:"source"`
+	Samples   int       `json:"samples,omitempty"`
+	FirstSeen time.Time `json:"firstSeen,omitempty"`
+	LastSeen  time.Time `json:"lastSeen,omitempty"`
+}
+
+// DefaultRedact lists the name fragments whose values are never kept as
+// examples or enum values.
+var DefaultRedact = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie",
+	"apikey", "api_key", "api-key", "session", "credit", "card", "cvv", "ssn",
+}
+
+// ignoredHeaders are request headers every client sends; they say nothing
+// about the API.
+var ignoredHeaders = map[string]bool{
+	"accept": true, "accept-encoding": true, "accept-language": true,
+	"authorization": true, "cache-control": true, "connection": true,
+	"content-length": true, "content-type": true, "cookie": true, "dnt": true,
+	"host": true, "if-modified-since": true, "if-none-match": true,
+	"origin": true, "pragma": true, "referer": true, "te": true,
+	"upgrade-insecure-requests": true, "user-agent": true, "x-real-ip": true,
+}
+
+// LearnConfig configures Learn.
+type LearnConfig struct {
+	// SampleRate is the fraction of requests observed, from 0 to 1.
+	// Defaults to 1.
+	SampleRate float64
+
+	// MaxBodyBytes skips bodies larger than this. Defaults to 64 KiB.
+	MaxBodyBytes int
+
+	// Redact lists name fragments, in addition to DefaultRedact, matched
+	// case-insensitively against body fields, query parameters and headers
+	// whose values must never be kept.
+	Redact []string
+
+	// PushInterval is how often changed schemas are sent to the server.
+	// Defaults to 30 seconds.
+	PushInterval time.Duration
+
+	// Next skips observing a request when it returns true.
+	Next func(c *fiber.Ctx) bool
+}
+
+// Learn observes requests and responses and infers the schemas of their
+// bodies, query parameters and headers per documented route: which fields
+// are always present, enum candidates, formats such as date-time, uuid and
+// email, and examples. Learned schemas replace the generated ones and are
+// pushed to the server with a "traffic" provenance whenever they change;
+// routes documented in Config.Routes keep their hand-written parts.
+//
+// Values of fields, parameters and headers whose names match a redaction
+// rule are never kept. Register must be called for learned schemas to be
+// pushed.
+func Learn(config ...LearnConfig) fiber.Handler {
+	cfg := LearnConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 64 << 10
+	}
+	if cfg.PushInterval <= 0 {
+		cfg.PushInterval = 30 * time.Second
+	}
+	redact := append(append([]string(nil), DefaultRedact...), cfg.Redact...)
+	l := &learner{
+		config: cfg,
+		routes: make(map[string]*observations),
+		redact: func(name string) bool {
+			name = strings.ToLower(name)
+			for _, fragment := range redact {
+				if strings.Contains(name, strings.ToLower(fragment)) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+
+	return func(c *fiber.Ctx) error {
+		app := c.App()
+		l.start.Do(func() { go l.pushLoop(app) })
+
+		if (cfg.Next != nil && cfg.Next(c)) || rand.Float64() >= cfg.SampleRate {
+			return c.Next()
+		}
+		route, params := routeTableFor(app).match(c.Method(), c.Path())
+		if route == nil {
+			return c.Next()
+		}
+
+		l.observeRequest(c, route, params)
+		if err := c.Next(); err != nil {
+			if handlerErr := app.ErrorHandler(c, err); handlerErr != nil {
+				return handlerErr
+			}
+		}
+		l.observeResponse(c, route)
+		return nil
+	}
+}
+
+type learner struct {
+	config LearnConfig
+	redact func(name string) bool
+	start  sync.Once
+
+	mu     sync.Mutex
+	routes map[string]*observations
+	pushed string
+}
+
+// observations is what was learned about one route.
+type observations struct {
+	method    string
+	path      string
+	requests  int
+	firstSeen time.Time
+	lastSeen  time.Time
+
+	pathParams map[string]*shape
+	query      map[string]*shape
+	headers    map[string]*shape
+	bodies     int
+	body       *shape
+	responses  map[int]*observedResponse
+}
+
+type observedResponse struct {
+	contentType string
+	body        *shape
+}
+
+func (l *learner) route(route *RouteInfo) *observations {
+	key := route.Method + " " + route.Path
+	obs, ok := l.routes[key]
+	if !ok {
+		obs = &observations{
+			method:     route.Method,
+			path:       route.Path,
+			pathParams: make(map[string]*shape),
+			query:      make(map[string]*shape),
+			headers:    make(map[string]*shape),
+			responses:  make(map[int]*observedResponse),
+		}
+		l.routes[key] = obs
+	}
+	return obs
+}
+
+func (l *learner) observeRequest(c *fiber.Ctx, route *RouteInfo, params map[string]string) {
+	var body interface{}
+	hasBody := false
+	if raw := c.Body(); len(raw) > 0 && len(raw) <= l.config.MaxBodyBytes && isJSON(c.Get(fiber.HeaderContentType)) {
+		if value, err := validate.DecodeJSON(raw); err == nil {
+			body, hasBody = value, true
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	obs := l.route(route)
+	now := time.Now().UTC()
+	if obs.requests == 0 {
+		obs.firstSeen = now
+	}
+	obs.requests++
+	obs.lastSeen = now
+
+	for name, value := range params {
+		// Path values point into the request buffer, which Fiber reuses.
+		value = utils.CopyString(value)
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		observeParam(obs.pathParams, name, value, l.redact(name), l.redact)
+	}
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		name := string(key)
+		observeParam(obs.query, name, string(value), l.redact(name), l.redact)
+	})
+	c.Context().Request.Header.VisitAll(func(key, value []byte) {
+		name := http.CanonicalHeaderKey(string(key))
+		lower := strings.ToLower(name)
+		if ignoredHeaders[lower] || strings.HasPrefix(lower, "sec-") || strings.HasPrefix(lower, "x-forwarded-") {
+			return
+		}
+		observeParam(obs.headers, name, string(value), l.redact(name), l.redact)
+	})
+
+	if hasBody {
+		if obs.body == nil {
+			obs.body = newShape()
+		}
+		obs.bodies++
+		obs.body.observe(body, false, l.redact)
+	}
+}
+
+func observeParam(shapes map[string]*shape, name, value string, redacted bool, redact func(string) bool) {
+	s, ok := shapes[name]
+	if !ok {
+		if len(shapes) >= maxProperties {
+			return
+		}
+		s = newShape()
+		shapes[name] = s
+	}
+	s.observe(parseParam(value), redacted, redact)
+}
+
+func (l *learner) observeResponse(c *fiber.Ctx, route *RouteInfo) {
+	status := c.Response().StatusCode()
+	contentType, _, _ := mime.ParseMediaType(string(c.Response().Header.ContentType()))
+	raw := c.Response().Body()
+
+	var body interface{}
+	hasBody := false
+	if len(raw) > 0 && len(raw) <= l.config.MaxBodyBytes && isJSON(contentType) {
+		if value, err := validate.DecodeJSON(raw); err == nil {
+			body, hasBody = value, true
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	obs := l.route(route)
+	resp, ok := obs.responses[status]
+	if !ok {
+		resp = &observedResponse{}
+		obs.responses[status] = resp
+	}
+	if len(raw) > 0 && contentType != "" {
+		resp.contentType = contentType
+	}
+	if hasBody {
+		if resp.body == nil {
+			resp.body = newShape()
+		}
+		resp.body.observe(body, false, l.redact)
+	}
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// apply documents a route with what was learned about it.
+func (obs *observations) apply(route RouteInfo) RouteInfo {
+	var params []Parameter
+	for _, param := range route.Parameters {
+		if s, ok := obs.pathParams[param.Name]; ok && param.In == "path" {
+			param.Schema = s.schema()
+		}
+		params = append(params, param)
+	}
+	params = append(params, learnedParams("query", obs.query, obs.requests, route.Parameters)...)
+	params = append(params, learnedParams("header", obs.headers, obs.requests, route.Parameters)...)
+	route.Parameters = params
+
+	if obs.body != nil {
+		route.RequestBody = &RequestBody{
+			Required: obs.bodies == obs.requests,
+			Content: map[string]MediaTypeObject{
+				fiber.MIMEApplicationJSON: {Schema: obs.body.schema()},
+			},
+		}
+	}
+
+	if len(obs.responses) > 0 {
+		// Generated success responses are guesses; once real successes were
+		// seen, only those are documented.
+		succeeded := false
+		for status := range obs.responses {
+			succeeded = succeeded || (status >= 200 && status < 300)
+		}
+		responses := make(map[string]Response, len(route.Responses)+len(obs.responses))
+		for code, response := range route.Responses {
+			if succeeded && strings.HasPrefix(code, "2") {
+				continue
+			}
+			responses[code] = response
+		}
+		for status, observed := range obs.responses {
+			code := strconv.Itoa(status)
+			response, ok := route.Responses[code]
+			if !ok || response.Description == "" {
+				response.Description = http.StatusText(status)
+			}
+			switch {
+			case observed.body != nil:
+				response.Content = map[string]MediaTypeObject{observed.contentType: {Schema: observed.body.schema()}}
+			case observed.contentType != "":
+				response.Content = map[string]MediaTypeObject{observed.contentType: {Schema: Schema{Type: "string"}}}
+			}
+			responses[code] = response
+		}
+		route.Responses = responses
+	}
+
+	route.Provenance = &Provenance{
+		Source:    ProvenanceTraffic,
+		Samples:   obs.requests,
+		FirstSeen: obs.firstSeen,
+		LastSeen:  obs.lastSeen,
+	}
+	return route
+}
+
+// learnedParams documents the observed parameters of one location that
+// the route does not already document. Parameters sent with every request
+// are required.
+func learnedParams(in string, shapes map[string]*shape, requests int, documented []Parameter) []Parameter {
+	var params []Parameter
+	for name, s := range shapes {
+		if hasParam(documented, in, name) {
+			continue
+		}
+		params = append(params, Parameter{
+			Name:     name,
+			In:       in,
+			Required: s.seen+s.nulls >= requests,
+			Schema:   s.schema(),
+		})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+func hasParam(params []Parameter, in, name string) bool {
+	for _, param := range params {
+		if param.In == in && strings.EqualFold(param.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// learnedRoutes documents the app with everything learned so far.
+func (l *learner) learnedRoutes(app *fiber.App, cfg Config) []RouteInfo {
+	routes := extractRoutes(app, cfg.Logger)
+
+	l.mu.Lock()
+	for i, route := range routes {
+		if obs, ok := l.routes[route.Method+" "+route.Path]; ok {
+			routes[i] = obs.apply(route)
+		}
+	}
+	l.mu.Unlock()
+
+	return applyOverrides(routes, cfg.Routes)
+}
+
+// pushLoop sends the learned routes to the server whenever their schemas
+// change.
+func (l *learner) pushLoop(app *fiber.App) {
+	done := make(chan struct{})
+	app.Hooks().OnShutdown(func() error {
+		close(done)
+		return nil
+	})
+
+	ticker := time.NewTicker(l.config.PushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.push(app)
+		case <-done:
+			return
+		}
+	}
+}
+
+func (l *learner) push(app *fiber.App) {
+	value, ok := registrations.Load(app)
+	if !ok {
+		return
+	}
+	reg := value.(*registration)
+	routes := l.learnedRoutes(app, reg.config)
+
+	fingerprint := schemaFingerprint(routes)
+	l.mu.Lock()
+	unchanged := fingerprint == l.pushed
+	l.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	if _, err := send(reg.config, reg.port, routes); err != nil {
+		reg.config.Logger.Warn("atomicdocs: failed to push learned schemas", "error", err)
+		return
+	}
+	l.mu.Lock()
+	l.pushed = fingerprint
+	l.mu.Unlock()
+	reg.config.Logger.Info("atomicdocs: pushed learned schemas", "routes", len(routes))
+}
+
+// schemaFingerprint identifies the documented shape of the routes, leaving
+// out provenance so new samples alone do not cause a push.
+func schemaFingerprint(routes []RouteInfo) string {
+	stripped := make([]RouteInfo, len(routes))
+	for i, route := range routes {
+		route.Provenance = nil
+		stripped[i] = route
+	}
+	data, _ := json.Marshal(stripped)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
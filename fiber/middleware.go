@@ -2,22 +2,32 @@ package atomicdocs
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"os"
 	"reflect"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/atomicdocs/schema"
 )
 
 type RouteInfo struct {
 	Method      string                 `json:"method"`
 	Path        string                 `json:"path"`
 	Handler     string                 `json:"handler"`
+	OperationID string                 `json:"operationId,omitempty"`
 	FilePath    string                 `json:"filePath,omitempty"`
 	Imports     []Import               `json:"imports,omitempty"`
 	Summary     string                 `json:"summary,omitempty"`
@@ -26,6 +36,7 @@ type RouteInfo struct {
 	Parameters  []Parameter            `json:"parameters,omitempty"`
 	RequestBody *RequestBody           `json:"requestBody,omitempty"`
 	Responses   map[string]Response    `json:"responses,omitempty"`
+	Provenance  *Provenance            `json:"provenance,omitempty"`
 }
 
 type Import struct {
@@ -33,40 +44,114 @@ type Import struct {
 	From string `json:"from"`
 }
 
-type Parameter struct {
-	Name     string      `json:"name"`
-	In       string      `json:"in"`
-	Required bool        `json:"required,omitempty"`
-	Schema   Schema      `json:"schema"`
-}
-
-type RequestBody struct {
-	Required bool                       `json:"required,omitempty"`
-	Content  map[string]MediaTypeObject `json:"content"`
-}
-
-type MediaTypeObject struct {
-	Schema Schema `json:"schema"`
-}
+// Schema, Parameter, RequestBody and MediaTypeObject are the server's own
+// types, so Validate checks requests the same way the server's mock does.
+type (
+	Schema          = schema.Schema
+	Parameter       = schema.Parameter
+	RequestBody     = schema.RequestBody
+	MediaTypeObject = schema.MediaTypeObject
+)
 
 type Response struct {
 	Description string                     `json:"description"`
 	Content     map[string]MediaTypeObject `json:"content,omitempty"`
 }
 
-type Schema struct {
-	Type       string            `json:"type,omitempty"`
-	Format     string            `json:"format,omitempty"`
-	Properties map[string]Schema `json:"properties,omitempty"`
-	Items      *Schema           `json:"items,omitempty"`
-}
-
 type RegistrationPayload struct {
 	Routes      []RouteInfo       `json:"routes"`
 	Port        int               `json:"port"`
 	SchemaFiles map[string]string `json:"schemaFiles"`
+	Info        *Info             `json:"info,omitempty"`
+	Servers     []Server          `json:"servers,omitempty"`
+	Tags        []Tag             `json:"tags,omitempty"`
+	GitSHA      string            `json:"gitSha,omitempty"`
+}
+
+type Info struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	TermsOfService string   `json:"termsOfService,omitempty"`
+	Contact        *Contact `json:"contact,omitempty"`
+	License        *License `json:"license,omitempty"`
+	Version        string   `json:"version"`
+}
+
+type Contact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Server struct {
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+type ServerVariable struct {
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default"`
+	Description string   `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+}
+
+type ExternalDocs struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// Config holds the optional document metadata sent along with the routes.
+type Config struct {
+	Info    *Info
+	Servers []Server
+	Tags    []Tag
+
+	// GitSHA is recorded with the registration in the server's version
+	// history.
+	GitSHA string
+
+	// Token is sent as a bearer token when the server requires one.
+	// Defaults to the ATOMICDOCS_TOKEN environment variable.
+	Token string
+
+	// Secret signs the payload with HMAC-SHA256 when the server is configured
+	// with a registration secret. Defaults to the ATOMICDOCS_SECRET
+	// environment variable.
+	Secret string
+
+	// ServerURL is the AtomicDocs server to register with. Defaults to
+	// http://localhost:6174.
+	ServerURL string
+
+	// ReadyTimeout bounds how long Register waits for the server to report
+	// ready. Defaults to 5 seconds.
+	ReadyTimeout time.Duration
+
+	// Logger receives debug output about route extraction. Defaults to
+	// slog.Default(), which drops debug messages unless configured otherwise.
+	Logger *slog.Logger
+
+	// Routes documents routes explicitly. An entry replaces the generated
+	// summary, description, tags, parameters, request body and responses of
+	// the route with the same method and path, as far as it sets them.
+	// Validate checks requests against the parameters and request bodies
+	// documented here.
+	Routes []RouteInfo
 }
 
+const defaultServerURL = "http://localhost:6174"
+
 func New(port int) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		path := c.Path()
@@ -78,6 +163,9 @@ func New(port int) fiber.Handler {
 			}
 			
 			req.Header.Set("X-App-Port", fmt.Sprintf("%d", port))
+			if authorization := c.Get("Authorization"); authorization != "" {
+				req.Header.Set("Authorization", authorization)
+			}
 			
 			client := &http.Client{}
 			resp, err := client.Do(req)
@@ -92,6 +180,9 @@ func New(port int) fiber.Handler {
 			}
 			
 			c.Set("Content-Type", resp.Header.Get("Content-Type"))
+			if challenge := resp.Header.Get("WWW-Authenticate"); challenge != "" {
+				c.Set("WWW-Authenticate", challenge)
+			}
 			return c.Status(resp.StatusCode).Send(body)
 		}
 		
@@ -99,46 +190,138 @@ func New(port int) fiber.Handler {
 	}
 }
 
-func Register(app *fiber.App, port int) {
-	routes := extractRoutes(app)
+func Register(app *fiber.App, port int, config ...Config) {
+	cfg := Config{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.ServerURL == "" {
+		cfg.ServerURL = defaultServerURL
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("ATOMICDOCS_TOKEN")
+	}
+	if cfg.Secret == "" {
+		cfg.Secret = os.Getenv("ATOMICDOCS_SECRET")
+	}
+	if cfg.ReadyTimeout == 0 {
+		cfg.ReadyTimeout = 5 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	
+	registrations.Store(app, &registration{port: port, config: cfg})
+	routes := documentRoutes(app, cfg)
+	documented.Store(app, newRouteTable(routes, cfg.Routes))
+	
+	if err := waitReady(cfg.ServerURL, cfg.ReadyTimeout); err != nil {
+		fmt.Printf("✗ AtomicDocs: Registration failed: %v\n", err)
+		return
+	}
+	
+	result, err := send(cfg, port, routes)
+	if err != nil {
+		fmt.Printf("✗ AtomicDocs: %v\n", err)
+		return
+	}
 	
+	printSummary(os.Stdout, result, len(routes))
+	fmt.Printf("📚 Docs: http://localhost:%d/docs\n", port)
+}
+
+// registrations remembers how each app was registered, keyed by
+// *fiber.App, so learned schemas can be pushed the same way.
+var registrations sync.Map
+
+type registration struct {
+	port   int
+	config Config
+}
+
+// send posts routes to the server's registration endpoint and returns its
+// reply.
+func send(cfg Config, port int, routes []RouteInfo) (*registrationResult, error) {
 	payload := RegistrationPayload{
 		Routes:      routes,
 		Port:        port,
 		SchemaFiles: make(map[string]string), // Empty for now
+		Info:        cfg.Info,
+		Servers:     cfg.Servers,
+		Tags:        cfg.Tags,
+		GitSHA:      cfg.GitSHA,
 	}
 	
 	data, _ := json.Marshal(payload)
-	req, _ := http.NewRequest("POST", "http://localhost:6174/api/register", bytes.NewBuffer(data))
+	req, _ := http.NewRequest("POST", strings.TrimSuffix(cfg.ServerURL, "/")+"/api/v1/register", bytes.NewBuffer(data))
 	req.Header.Set("Content-Type", "application/json")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	}
+	if cfg.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-AtomicDocs-Timestamp", timestamp)
+		req.Header.Set("X-AtomicDocs-Signature", signPayload(cfg.Secret, timestamp, data))
+	}
 	
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		fmt.Printf("✗ AtomicDocs: Registration failed: %v\n", err)
-		return
+		return nil, fmt.Errorf("Registration failed: %w", err)
 	}
 	defer resp.Body.Close()
 	
-	fmt.Printf("✓ AtomicDocs: Registered %d routes\n", len(routes))
-	fmt.Printf("📚 Docs: http://localhost:%d/docs\n", port)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Registration rejected (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	// Older servers only reply with a status; their result is empty.
+	var result registrationResult
+	json.NewDecoder(resp.Body).Decode(&result)
+	return &result, nil
 }
 
-func extractRoutes(app *fiber.App) []RouteInfo {
+// waitReady polls the server's /readyz endpoint until it answers 200 or the
+// timeout passes.
+func waitReady(serverURL string, timeout time.Duration) error {
+	client := &http.Client{Timeout: time.Second}
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Get(strings.TrimSuffix(serverURL, "/") + "/readyz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("server not ready (%d)", resp.StatusCode)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("AtomicDocs server at %s not ready: %w", serverURL, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func signPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func extractRoutes(app *fiber.App, logger *slog.Logger) []RouteInfo {
 	routes := []RouteInfo{}
-	allRoutes := app.GetRoutes()
+	allRoutes := app.GetRoutes(true)
 	
-	fmt.Printf("Debug: Found %d total routes\n", len(allRoutes))
+	logger.Debug("atomicdocs: extracting routes", "total", len(allRoutes))
 	
 	for _, route := range allRoutes {
-		fmt.Printf("Debug: Processing route %s %s\n", route.Method, route.Path)
-		
 		if strings.HasPrefix(route.Path, "/docs") {
-			fmt.Printf("Debug: Skipping docs route\n")
+			logger.Debug("atomicdocs: skipping docs route", "method", route.Method, "path", route.Path)
 			continue
 		}
 		
 		if len(route.Handlers) == 0 {
-			fmt.Printf("Debug: No handlers for route\n")
+			logger.Debug("atomicdocs: skipping route without handlers", "method", route.Method, "path", route.Path)
 			continue
 		}
 		
@@ -163,10 +346,9 @@ func extractRoutes(app *fiber.App) []RouteInfo {
 		}
 		
 		routes = append(routes, info)
-		fmt.Printf("Debug: Added route %s %s\n", info.Method, info.Path)
+		logger.Debug("atomicdocs: added route", "method", info.Method, "path", info.Path)
 	}
 	
-	fmt.Printf("Debug: Returning %d routes\n", len(routes))
 	return routes
 }
 
@@ -0,0 +1,204 @@
+package atomicdocs
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// documented holds the routes each app was registered with, keyed by
+// *fiber.App, so Validate and Contract check traffic against what Register
+// documented. Schemas learned later from traffic are not included.
+var documented sync.Map
+
+// routeTable matches requests to documented routes.
+type routeTable struct {
+	routes []*documentedRoute
+}
+
+type documentedRoute struct {
+	info     RouteInfo
+	segments []routeSegment
+	literals int
+
+	// explicitParams and explicitBody are set when Config.Routes documents
+	// the route's parameters or request body, rather than extractRoutes
+	// guessing them from the path.
+	explicitParams bool
+	explicitBody   bool
+}
+
+type routeSegment struct {
+	literal  string
+	param    string
+	optional bool
+	rest     bool
+	any      bool
+}
+
+// documentRoutes is the documentation of an app: the routes extracted from
+// the app with the entries of Config.Routes applied on top.
+func documentRoutes(app *fiber.App, cfg Config) []RouteInfo {
+	return applyOverrides(extractRoutes(app, cfg.Logger), cfg.Routes)
+}
+
+// applyOverrides merges each override into the route with the same method
+// and path, adding overrides that match no route.
+func applyOverrides(routes []RouteInfo, overrides []RouteInfo) []RouteInfo {
+	for _, override := range overrides {
+		found := false
+		for i := range routes {
+			if strings.EqualFold(routes[i].Method, override.Method) && routes[i].Path == override.Path {
+				routes[i] = mergeRoute(routes[i], override)
+				found = true
+			}
+		}
+		if !found {
+			routes = append(routes, override)
+		}
+	}
+	return routes
+}
+
+// mergeRoute replaces the generated parts of a route with the ones the
+// override sets.
+func mergeRoute(route, override RouteInfo) RouteInfo {
+	if override.OperationID != "" {
+		route.OperationID = override.OperationID
+	}
+	if override.Summary != "" {
+		route.Summary = override.Summary
+	}
+	if override.Description != "" {
+		route.Description = override.Description
+	}
+	if len(override.Tags) > 0 {
+		route.Tags = override.Tags
+	}
+	if len(override.Parameters) > 0 {
+		route.Parameters = override.Parameters
+	}
+	if override.RequestBody != nil {
+		route.RequestBody = override.RequestBody
+	}
+	if len(override.Responses) > 0 {
+		route.Responses = override.Responses
+	}
+	return route
+}
+
+// newRouteTable builds the table of routes, marking the parts documented by
+// the entries of overrides.
+func newRouteTable(routes []RouteInfo, overrides []RouteInfo) *routeTable {
+	t := &routeTable{}
+	for _, info := range routes {
+		rt := &documentedRoute{info: info}
+		for _, override := range overrides {
+			if strings.EqualFold(info.Method, override.Method) && info.Path == override.Path {
+				rt.explicitParams = rt.explicitParams || len(override.Parameters) > 0
+				rt.explicitBody = rt.explicitBody || override.RequestBody != nil
+			}
+		}
+		for _, part := range splitPath(info.Path) {
+			seg := routeSegment{}
+			switch {
+			case part == "*" || part == "+":
+				seg.rest = true
+			case strings.HasPrefix(part, ":"):
+				name := part[1:]
+				if strings.HasSuffix(name, "?") {
+					name = strings.TrimSuffix(name, "?")
+					seg.optional = true
+				}
+				if i := strings.Index(name, "<"); i >= 0 {
+					name = name[:i]
+				}
+				seg.param = name
+			case strings.ContainsAny(part, ":*+"):
+				// Parameters inside a segment ("/:from-:to") match the
+				// whole segment.
+				seg.any = true
+			default:
+				seg.literal = strings.ToLower(part)
+				rt.literals++
+			}
+			rt.segments = append(rt.segments, seg)
+		}
+		t.routes = append(t.routes, rt)
+	}
+	return t
+}
+
+// routeTableFor returns the table of the routes the app was registered
+// with. Apps that have not registered yet are documented from their routes
+// as they stand.
+func routeTableFor(app *fiber.App) *routeTable {
+	if t, ok := documented.Load(app); ok {
+		return t.(*routeTable)
+	}
+	t, _ := documented.LoadOrStore(app, newRouteTable(documentRoutes(app, Config{Logger: slog.Default()}), nil))
+	return t.(*routeTable)
+}
+
+// match finds the documented route for a request and extracts its path
+// parameters. Routes with more literal segments win.
+func (t *routeTable) match(method, path string) (*RouteInfo, map[string]string) {
+	rt, params := t.find(method, path)
+	if rt == nil {
+		return nil, nil
+	}
+	return &rt.info, params
+}
+
+// find is match returning the table entry.
+func (t *routeTable) find(method, path string) (*documentedRoute, map[string]string) {
+	parts := splitPath(path)
+	var best *documentedRoute
+	var bestParams map[string]string
+	for _, rt := range t.routes {
+		if !strings.EqualFold(rt.info.Method, method) {
+			continue
+		}
+		params, ok := rt.match(parts)
+		if !ok {
+			continue
+		}
+		if best == nil || rt.literals > best.literals {
+			best, bestParams = rt, params
+		}
+	}
+	return best, bestParams
+}
+
+func (rt *documentedRoute) match(parts []string) (map[string]string, bool) {
+	params := map[string]string{}
+	for i, seg := range rt.segments {
+		if seg.rest {
+			return params, true
+		}
+		if i >= len(parts) {
+			if seg.optional && i == len(rt.segments)-1 {
+				return params, true
+			}
+			return nil, false
+		}
+		switch {
+		case seg.param != "":
+			params[seg.param] = parts[i]
+		case seg.any:
+		case seg.literal != strings.ToLower(parts[i]):
+			return nil, false
+		}
+	}
+	return params, len(parts) == len(rt.segments)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,90 @@
+package atomicdocs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// registrationResult is the server's reply to a registration: how each
+// route's schemas were resolved, and where the spec is not valid OpenAPI.
+type registrationResult struct {
+	Version  int                 `json:"version"`
+	Summary  registrationSummary `json:"summary"`
+	Routes   []routeAnalysis     `json:"routes"`
+	Warnings []specProblem       `json:"warnings"`
+}
+
+type registrationSummary struct {
+	Routes           int            `json:"routes"`
+	Imports          int            `json:"imports"`
+	Resolved         int            `json:"resolved"`
+	Parsed           int            `json:"parsed"`
+	Libraries        map[string]int `json:"libraries"`
+	DefaultResponses int            `json:"defaultResponses"`
+	MissingBodies    int            `json:"missingBodies"`
+	Warnings         int            `json:"warnings"`
+}
+
+type routeAnalysis struct {
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Warnings []string `json:"warnings"`
+}
+
+type specProblem struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// maxListed caps the warnings printed; the rest are counted.
+const maxListed = 10
+
+// printSummary writes a readable summary of a registration result.
+func printSummary(w io.Writer, result *registrationResult, routes int) {
+	s := result.Summary
+	if result.Version > 0 {
+		fmt.Fprintf(w, "✓ AtomicDocs: Registered %d routes (version %d)\n", routes, result.Version)
+	} else {
+		fmt.Fprintf(w, "✓ AtomicDocs: Registered %d routes\n", routes)
+	}
+	if s.Imports > 0 {
+		libraries := make([]string, 0, len(s.Libraries))
+		for library, n := range s.Libraries {
+			libraries = append(libraries, fmt.Sprintf("%s: %d", library, n))
+		}
+		sort.Strings(libraries)
+		parsed := ""
+		if len(libraries) > 0 {
+			parsed = " (" + strings.Join(libraries, ", ") + ")"
+		}
+		fmt.Fprintf(w, "  Schemas: %d of %d imports resolved, %d parsed%s\n", s.Resolved, s.Imports, s.Parsed, parsed)
+	}
+	if s.DefaultResponses > 0 {
+		fmt.Fprintf(w, "  %d routes use the default responses\n", s.DefaultResponses)
+	}
+	if s.MissingBodies > 0 {
+		fmt.Fprintf(w, "  %d routes document no request body\n", s.MissingBodies)
+	}
+
+	var lines []string
+	for _, route := range result.Routes {
+		for _, warning := range route.Warnings {
+			lines = append(lines, route.Method+" "+route.Path+": "+warning)
+		}
+	}
+	for _, problem := range result.Warnings {
+		lines = append(lines, "spec: "+problem.Message+" (at "+problem.Pointer+")")
+	}
+	if len(lines) > 0 {
+		fmt.Fprintf(w, "⚠ AtomicDocs: %d warnings\n", len(lines))
+		for i, line := range lines {
+			if i == maxListed {
+				fmt.Fprintf(w, "    … and %d more\n", len(lines)-maxListed)
+				break
+			}
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+}
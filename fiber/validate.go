@@ -0,0 +1,120 @@
+package atomicdocs
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/yourusername/atomicdocs/validate"
+)
+
+// ProblemContentType is the media type of RFC 7807 error responses.
+const ProblemContentType = "application/problem+json"
+
+// FieldError describes one place where a request or response breaks its
+// documented schema.
+type FieldError = validate.FieldError
+
+// Problem is an RFC 7807 problem details object. Errors lists every
+// mismatch found.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// ValidateConfig configures Validate.
+type ValidateConfig struct {
+	// Next skips validation for a request when it returns true.
+	Next func(c *fiber.Ctx) bool
+
+	// ProblemType is the "type" URI of the problems returned. Defaults to
+	// "about:blank".
+	ProblemType string
+}
+
+// Validate checks requests against the routes documented in Config.Routes:
+// path, query and header parameters and JSON bodies must match their
+// documented types, required fields, enums, formats and constraints.
+// Mismatches are answered with a problem+json error and never reach the
+// handler. Only the parameters and bodies a Config.Routes entry sets are
+// enforced; what Register guesses from the path is not, so routes without
+// an entry pass through.
+//
+// Register the middleware with app.Use before the routes. Requests are
+// checked against the routes as last passed to Register.
+func Validate(config ...ValidateConfig) fiber.Handler {
+	cfg := ValidateConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.ProblemType == "" {
+		cfg.ProblemType = "about:blank"
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		route, params := routeTableFor(c.App()).find(c.Method(), c.Path())
+		if route == nil || !(route.explicitParams || route.explicitBody) {
+			return c.Next()
+		}
+
+		if status, errs := validateRequest(c, route, params); len(errs) > 0 {
+			return sendProblem(c, Problem{
+				Type:     cfg.ProblemType,
+				Title:    utils.StatusMessage(status),
+				Status:   status,
+				Detail:   "The request does not match the documented API.",
+				Instance: c.OriginalURL(),
+				Errors:   errs,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// validateRequest returns the errors of a request and the status to
+// report them with, checking only what Config.Routes documents.
+func validateRequest(c *fiber.Ctx, route *documentedRoute, params map[string]string) (int, []FieldError) {
+	var parameters []Parameter
+	if route.explicitParams {
+		parameters = route.info.Parameters
+	}
+	var body *RequestBody
+	if route.explicitBody {
+		body = route.info.RequestBody
+	}
+
+	pathParams := make(map[string]string, len(params))
+	for name, value := range params {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		pathParams[name] = value
+	}
+	return validate.CheckRequest(parameters, body, validate.Request{
+		PathParams: pathParams,
+		Query: func(name string) []string {
+			var values []string
+			for _, value := range c.Context().QueryArgs().PeekMulti(name) {
+				values = append(values, string(value))
+			}
+			return values
+		},
+		Header: func(name string) string {
+			return c.Get(name)
+		},
+		ContentType: c.Get(fiber.HeaderContentType),
+		Body:        c.Body(),
+	}, nil)
+}
+
+func sendProblem(c *fiber.Ctx, problem Problem) error {
+	return c.Status(problem.Status).JSON(problem, ProblemContentType)
+}
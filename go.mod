@@ -5,6 +5,7 @@ go 1.22.2
 require (
 	github.com/gofiber/fiber/v2 v2.52.11
 	github.com/valyala/fasthttp v1.51.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TimestampHeader = "X-AtomicDocs-Timestamp"
+	SignatureHeader = "X-AtomicDocs-Signature"
+
+	defaultMaxSkew = 5 * time.Minute
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing registration credentials")
+	ErrInvalidToken       = errors.New("invalid registration token")
+	ErrInvalidSignature   = errors.New("invalid payload signature")
+	ErrStaleTimestamp     = errors.New("timestamp outside the allowed window")
+	ErrReplayed           = errors.New("signature was already used")
+)
+
+// RegistrationConfig lists the credentials accepted by /api/register. Tokens
+// are sent as "Authorization: Bearer <token>". Secrets are used to sign the
+// payload: the client sends the unix timestamp in X-AtomicDocs-Timestamp and
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)) in
+// X-AtomicDocs-Signature.
+//
+// Per-app entries are keyed by the app's port and take precedence over the
+// shared token and secret. With nothing configured, registration is open.
+type RegistrationConfig struct {
+	Token          string            `json:"token,omitempty"`
+	Tokens         map[string]string `json:"tokens,omitempty"`
+	Secret         string            `json:"secret,omitempty"`
+	Secrets        map[string]string `json:"secrets,omitempty"`
+	MaxSkewSeconds int               `json:"maxSkewSeconds,omitempty"`
+}
+
+func (c RegistrationConfig) Enabled() bool {
+	return c.Token != "" || c.Secret != "" || len(c.Tokens) > 0 || len(c.Secrets) > 0
+}
+
+// Credentials are the authentication headers of a registration request.
+type Credentials struct {
+	Authorization string
+	Timestamp     string
+	Signature     string
+}
+
+// RegistrationVerifier checks registration requests against the configured
+// tokens and secrets, remembering recent signatures to reject replays.
+type RegistrationVerifier struct {
+	config  RegistrationConfig
+	maxSkew time.Duration
+	now     func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func NewRegistrationVerifier(cfg RegistrationConfig) *RegistrationVerifier {
+	maxSkew := defaultMaxSkew
+	if cfg.MaxSkewSeconds > 0 {
+		maxSkew = time.Duration(cfg.MaxSkewSeconds) * time.Second
+	}
+	return &RegistrationVerifier{
+		config:  cfg,
+		maxSkew: maxSkew,
+		now:     time.Now,
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// Verify returns nil when the request may register routes for app.
+func (v *RegistrationVerifier) Verify(app string, creds Credentials, body []byte) error {
+	if !v.config.Enabled() {
+		return nil
+	}
+
+	token, secret := v.config.Token, v.config.Secret
+	if t, s := v.config.Tokens[app], v.config.Secrets[app]; t != "" || s != "" {
+		token, secret = t, s
+	}
+
+	if creds.Signature != "" && secret != "" {
+		return v.verifySignature(secret, creds, body)
+	}
+
+	bearer := strings.TrimSpace(strings.TrimPrefix(creds.Authorization, "Bearer "))
+	if bearer != "" && token != "" {
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+			return nil
+		}
+		return ErrInvalidToken
+	}
+
+	if creds.Signature != "" || bearer != "" {
+		// Credentials of a kind this app does not accept.
+		if secret == "" {
+			return ErrInvalidToken
+		}
+		return ErrInvalidSignature
+	}
+	return ErrMissingCredentials
+}
+
+func (v *RegistrationVerifier) verifySignature(secret string, creds Credentials, body []byte) error {
+	ts, err := strconv.ParseInt(creds.Timestamp, 10, 64)
+	if err != nil {
+		return ErrStaleTimestamp
+	}
+	now := v.now()
+	sent := time.Unix(ts, 0)
+	if sent.Before(now.Add(-v.maxSkew)) || sent.After(now.Add(v.maxSkew)) {
+		return ErrStaleTimestamp
+	}
+
+	expected := Sign(secret, creds.Timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(creds.Signature)) {
+		return ErrInvalidSignature
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for sig, expires := range v.seen {
+		if now.After(expires) {
+			delete(v.seen, sig)
+		}
+	}
+	if _, ok := v.seen[creds.Signature]; ok {
+		return ErrReplayed
+	}
+	v.seen[creds.Signature] = sent.Add(v.maxSkew)
+	return nil
+}
+
+// Sign computes the X-AtomicDocs-Signature value for a payload.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
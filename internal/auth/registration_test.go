@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyOpenWithoutConfig(t *testing.T) {
+	v := NewRegistrationVerifier(RegistrationConfig{})
+	if err := v.Verify("3000", Credentials{}, []byte("{}")); err != nil {
+		t.Fatalf("Verify() = %v, want nil", err)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	v := NewRegistrationVerifier(RegistrationConfig{
+		Token:  "shared",
+		Tokens: map[string]string{"4000": "per-app"},
+	})
+
+	tests := []struct {
+		name          string
+		app           string
+		authorization string
+		want          error
+	}{
+		{"shared token", "3000", "Bearer shared", nil},
+		{"wrong token", "3000", "Bearer nope", ErrInvalidToken},
+		{"missing", "3000", "", ErrMissingCredentials},
+		{"per-app token", "4000", "Bearer per-app", nil},
+		{"shared token for per-app app", "4000", "Bearer shared", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Verify(tt.app, Credentials{Authorization: tt.authorization}, []byte("{}"))
+			if err != tt.want {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"port":3000}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		sent      time.Time
+		body      []byte
+		signature string
+		want      error
+	}{
+		{"valid", "s3cret", now, body, "", nil},
+		{"within skew", "s3cret", now.Add(-4 * time.Minute), body, "", nil},
+		{"too old", "s3cret", now.Add(-6 * time.Minute), body, "", ErrStaleTimestamp},
+		{"from the future", "s3cret", now.Add(6 * time.Minute), body, "", ErrStaleTimestamp},
+		{"wrong secret", "other", now, body, "", ErrInvalidSignature},
+		{"tampered body", "s3cret", now, []byte(`{"port":3001}`), Sign("s3cret", strconv.FormatInt(now.Unix(), 10), body), ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewRegistrationVerifier(RegistrationConfig{Secret: "s3cret"})
+			v.now = func() time.Time { return now }
+
+			timestamp := strconv.FormatInt(tt.sent.Unix(), 10)
+			signature := tt.signature
+			if signature == "" {
+				signature = Sign(tt.secret, timestamp, tt.body)
+			}
+			err := v.Verify("3000", Credentials{Timestamp: timestamp, Signature: signature}, tt.body)
+			if err != tt.want {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureBadTimestamp(t *testing.T) {
+	v := NewRegistrationVerifier(RegistrationConfig{Secret: "s3cret"})
+	creds := Credentials{Timestamp: "yesterday", Signature: Sign("s3cret", "yesterday", nil)}
+	if err := v.Verify("3000", creds, nil); err != ErrStaleTimestamp {
+		t.Fatalf("Verify() = %v, want %v", err, ErrStaleTimestamp)
+	}
+}
+
+func TestVerifyRejectsReplay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := NewRegistrationVerifier(RegistrationConfig{Secret: "s3cret", MaxSkewSeconds: 60})
+	v.now = func() time.Time { return now }
+
+	body := []byte(`{"port":3000}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	creds := Credentials{Timestamp: timestamp, Signature: Sign("s3cret", timestamp, body)}
+
+	if err := v.Verify("3000", creds, body); err != nil {
+		t.Fatalf("first Verify() = %v, want nil", err)
+	}
+	if err := v.Verify("3000", creds, body); err != ErrReplayed {
+		t.Fatalf("second Verify() = %v, want %v", err, ErrReplayed)
+	}
+
+	// Once the window has passed the timestamp is stale, and the old
+	// signature is forgotten on the next successful check.
+	now = now.Add(2 * time.Minute)
+	if err := v.Verify("3000", creds, body); err != ErrStaleTimestamp {
+		t.Fatalf("late Verify() = %v, want %v", err, ErrStaleTimestamp)
+	}
+	timestamp = strconv.FormatInt(now.Unix(), 10)
+	fresh := Credentials{Timestamp: timestamp, Signature: Sign("s3cret", timestamp, body)}
+	if err := v.Verify("3000", fresh, body); err != nil {
+		t.Fatalf("fresh Verify() = %v, want nil", err)
+	}
+	if len(v.seen) != 1 {
+		t.Errorf("seen holds %d signatures, want only the fresh one", len(v.seen))
+	}
+}
+
+func TestVerifyWrongKindOfCredentials(t *testing.T) {
+	tokenOnly := NewRegistrationVerifier(RegistrationConfig{Token: "shared"})
+	creds := Credentials{Timestamp: "1", Signature: "sha256=00"}
+	if err := tokenOnly.Verify("3000", creds, nil); err != ErrInvalidToken {
+		t.Errorf("signature against token config: Verify() = %v, want %v", err, ErrInvalidToken)
+	}
+
+	secretOnly := NewRegistrationVerifier(RegistrationConfig{Secret: "s3cret"})
+	if err := secretOnly.Verify("3000", Credentials{Authorization: "Bearer shared"}, nil); err != ErrInvalidSignature {
+		t.Errorf("token against secret config: Verify() = %v, want %v", err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tickets issue short-lived credentials for one app's event stream.
+// Browsers cannot set headers on an EventSource, so the docs page is
+// rendered with a ticket for the viewer it was served to and passes it in
+// the stream URL. Tickets are signed with a key made when the server starts
+// and stop working when it restarts.
+type Tickets struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+func NewTickets(ttl time.Duration) *Tickets {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("auth: no randomness for ticket key: " + err.Error())
+	}
+	return &Tickets{key: key, ttl: ttl, now: time.Now}
+}
+
+// Issue returns a ticket that identifies v for app until the ttl passes.
+func (t *Tickets) Issue(app string, v Viewer) string {
+	expires := strconv.FormatInt(t.now().Add(t.ttl).Unix(), 10)
+	authenticated := "0"
+	if v.Authenticated {
+		authenticated = "1"
+	}
+	name := base64.RawURLEncoding.EncodeToString([]byte(v.Name))
+	return expires + "." + authenticated + "." + name + "." + t.sign(app, expires, authenticated, name)
+}
+
+// Verify returns the viewer a ticket was issued to, if it was issued for
+// app and has not expired.
+func (t *Tickets) Verify(app, ticket string) (Viewer, bool) {
+	parts := strings.Split(ticket, ".")
+	if len(parts) != 4 {
+		return Viewer{}, false
+	}
+	expires, authenticated, name, signature := parts[0], parts[1], parts[2], parts[3]
+	if !hmac.Equal([]byte(signature), []byte(t.sign(app, expires, authenticated, name))) {
+		return Viewer{}, false
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || t.now().After(time.Unix(unix, 0)) {
+		return Viewer{}, false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(name)
+	if err != nil {
+		return Viewer{}, false
+	}
+	return Viewer{Authenticated: authenticated == "1", Name: string(decoded)}, true
+}
+
+func (t *Tickets) sign(app, expires, authenticated, name string) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(app + "." + expires + "." + authenticated + "." + name))
+	return hex.EncodeToString(mac.Sum(nil))
+}
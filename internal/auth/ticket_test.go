@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTickets(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tickets := NewTickets(time.Hour)
+	tickets.now = func() time.Time { return now }
+
+	alice := Viewer{Authenticated: true, Name: "alice.example"}
+	ticket := tickets.Issue("3000", alice)
+
+	if got, ok := tickets.Verify("3000", ticket); !ok || got != alice {
+		t.Fatalf("Verify() = %+v, %v, want %+v, true", got, ok, alice)
+	}
+	if _, ok := tickets.Verify("4000", ticket); ok {
+		t.Error("ticket for app 3000 was accepted for app 4000")
+	}
+	if _, ok := NewTickets(time.Hour).Verify("3000", ticket); ok {
+		t.Error("ticket was accepted with another key")
+	}
+
+	parts := strings.Split(ticket, ".")
+	parts[2] = "Ym9i" // "bob"
+	if _, ok := tickets.Verify("3000", strings.Join(parts, ".")); ok {
+		t.Error("ticket with a changed name was accepted")
+	}
+	for _, bad := range []string{"", "garbage", "1.2.3", "1.2.3.4.5"} {
+		if _, ok := tickets.Verify("3000", bad); ok {
+			t.Errorf("Verify(%q) was accepted", bad)
+		}
+	}
+
+	now = now.Add(time.Hour + time.Second)
+	if _, ok := tickets.Verify("3000", ticket); ok {
+		t.Error("expired ticket was accepted")
+	}
+}
+
+func TestAnonymousTicket(t *testing.T) {
+	tickets := NewTickets(time.Hour)
+
+	ticket := tickets.Issue("3000", Viewer{})
+	if got, ok := tickets.Verify("3000", ticket); !ok || got.Authenticated {
+		t.Fatalf("Verify() = %+v, %v, want an anonymous viewer", got, ok)
+	}
+
+	parts := strings.Split(ticket, ".")
+	parts[1] = "1"
+	if _, ok := tickets.Verify("3000", strings.Join(parts, ".")); ok {
+		t.Error("anonymous ticket marked authenticated was accepted")
+	}
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"net"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// ViewerConfig controls who may read the docs. Viewers authenticate with
+// basic auth, a static bearer token, or a header set by a trusted reverse
+// proxy; the header is ignored unless the request comes from one of
+// TrustedProxies. Apps are public unless configured otherwise; operations matching
+// HiddenTags or HiddenOperations are only shown to authenticated viewers.
+type ViewerConfig struct {
+	Basic          map[string]string `json:"basic,omitempty"`
+	Tokens         []string          `json:"tokens,omitempty"`
+	TrustedHeader  string            `json:"trustedHeader,omitempty"`
+	TrustedProxies []string          `json:"trustedProxies,omitempty"`
+
+	Visibility       string             `json:"visibility,omitempty"`
+	HiddenTags       []string           `json:"hiddenTags,omitempty"`
+	HiddenOperations []string           `json:"hiddenOperations,omitempty"`
+	Apps             map[string]AppRule `json:"apps,omitempty"`
+}
+
+// AppRule overrides the viewer rules for one app, keyed by port. Hidden tags
+// and operations add to the global ones.
+type AppRule struct {
+	Visibility       string   `json:"visibility,omitempty"`
+	HiddenTags       []string `json:"hiddenTags,omitempty"`
+	HiddenOperations []string `json:"hiddenOperations,omitempty"`
+}
+
+// ViewerRequest is what the viewer check needs to know about a request.
+type ViewerRequest struct {
+	Authorization string
+	RemoteIP      net.IP
+	Header        func(name string) string
+}
+
+// Viewer is the identity a request was resolved to.
+type Viewer struct {
+	Authenticated bool
+	Name          string
+}
+
+type ViewerAuth struct {
+	config  ViewerConfig
+	proxies []*net.IPNet
+}
+
+func NewViewerAuth(cfg ViewerConfig) *ViewerAuth {
+	a := &ViewerAuth{config: cfg}
+	for _, entry := range cfg.TrustedProxies {
+		if !strings.Contains(entry, "/") {
+			if strings.Contains(entry, ":") {
+				entry += "/128"
+			} else {
+				entry += "/32"
+			}
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			a.proxies = append(a.proxies, network)
+		}
+	}
+	return a
+}
+
+// Identify resolves the viewer behind a request. Requests without valid
+// credentials yield an anonymous viewer.
+func (a *ViewerAuth) Identify(r ViewerRequest) Viewer {
+	if a.config.TrustedHeader != "" && a.trusted(r.RemoteIP) {
+		if user := strings.TrimSpace(r.Header(a.config.TrustedHeader)); user != "" {
+			return Viewer{Authenticated: true, Name: user}
+		}
+	}
+
+	scheme, value, _ := strings.Cut(r.Authorization, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			break
+		}
+		user, pass, ok := strings.Cut(string(decoded), ":")
+		want, exists := a.config.Basic[user]
+		if ok && exists && subtle.ConstantTimeCompare([]byte(pass), []byte(want)) == 1 {
+			return Viewer{Authenticated: true, Name: user}
+		}
+	case "bearer":
+		for _, token := range a.config.Tokens {
+			if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(value)), []byte(token)) == 1 {
+				return Viewer{Authenticated: true, Name: "token"}
+			}
+		}
+	}
+
+	return Viewer{}
+}
+
+// trusted reports whether a request from ip may set the trusted header.
+// With no trusted proxies configured, no one may.
+func (a *ViewerAuth) trusted(ip net.IP) bool {
+	for _, network := range a.proxies {
+		if ip != nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanView reports whether the viewer may see the app's docs at all.
+func (a *ViewerAuth) CanView(app string, v Viewer) bool {
+	visibility := a.config.Visibility
+	if rule, ok := a.config.Apps[app]; ok && rule.Visibility != "" {
+		visibility = rule.Visibility
+	}
+	return visibility != VisibilityPrivate || v.Authenticated
+}
+
+// Private reports whether any app can be private, in which case data about
+// all apps, such as metrics, is only shown to authenticated viewers.
+func (a *ViewerAuth) Private() bool {
+	if a.config.Visibility == VisibilityPrivate {
+		return true
+	}
+	for _, rule := range a.config.Apps {
+		if rule.Visibility == VisibilityPrivate {
+			return true
+		}
+	}
+	return false
+}
+
+// Hidden reports whether an operation must be left out of the docs shown to
+// the viewer. Operations are written as "METHOD /path"; ":id" and "{id}"
+// path styles match each other.
+func (a *ViewerAuth) Hidden(app string, v Viewer, method, path string, tags []string) bool {
+	if v.Authenticated {
+		return false
+	}
+
+	rule := a.config.Apps[app]
+	for _, hidden := range [][]string{a.config.HiddenTags, rule.HiddenTags} {
+		for _, name := range hidden {
+			for _, tag := range tags {
+				if strings.EqualFold(tag, name) {
+					return true
+				}
+			}
+		}
+	}
+
+	key := openapi.PathKey(path)
+	for _, hidden := range [][]string{a.config.HiddenOperations, rule.HiddenOperations} {
+		for _, op := range hidden {
+			fields := strings.Fields(op)
+			if len(fields) == 2 && strings.EqualFold(fields[0], method) && openapi.PathKey(fields[1]) == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Filters reports whether any operation can be hidden for the app, letting
+// callers skip filtering entirely.
+func (a *ViewerAuth) Filters(app string) bool {
+	rule := a.config.Apps[app]
+	return len(a.config.HiddenTags)+len(a.config.HiddenOperations)+len(rule.HiddenTags)+len(rule.HiddenOperations) > 0
+}
+
+// Challenge is the WWW-Authenticate value sent with 401 responses.
+func (a *ViewerAuth) Challenge() string {
+	if len(a.config.Basic) > 0 {
+		return `Basic realm="AtomicDocs", charset="UTF-8"`
+	}
+	return `Bearer realm="AtomicDocs"`
+}
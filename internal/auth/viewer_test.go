@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net"
+	"testing"
+)
+
+func request(authorization, ip string, headers map[string]string) ViewerRequest {
+	return ViewerRequest{
+		Authorization: authorization,
+		RemoteIP:      net.ParseIP(ip),
+		Header:        func(name string) string { return headers[name] },
+	}
+}
+
+func basic(user, pass string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+}
+
+func TestIdentify(t *testing.T) {
+	a := NewViewerAuth(ViewerConfig{
+		Basic:          map[string]string{"alice": "wonderland"},
+		Tokens:         []string{"t0ken"},
+		TrustedHeader:  "X-Forwarded-User",
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.5", "::1"},
+	})
+	user := map[string]string{"X-Forwarded-User": "bob"}
+
+	tests := []struct {
+		name string
+		req  ViewerRequest
+		want Viewer
+	}{
+		{"anonymous", request("", "203.0.113.1", nil), Viewer{}},
+		{"basic", request(basic("alice", "wonderland"), "203.0.113.1", nil), Viewer{Authenticated: true, Name: "alice"}},
+		{"basic wrong password", request(basic("alice", "nope"), "203.0.113.1", nil), Viewer{}},
+		{"basic unknown user", request(basic("mallory", ""), "203.0.113.1", nil), Viewer{}},
+		{"basic garbage", request("Basic !!!", "203.0.113.1", nil), Viewer{}},
+		{"bearer", request("Bearer t0ken", "203.0.113.1", nil), Viewer{Authenticated: true, Name: "token"}},
+		{"bearer lower case scheme", request("bearer t0ken", "203.0.113.1", nil), Viewer{Authenticated: true, Name: "token"}},
+		{"bearer wrong token", request("Bearer nope", "203.0.113.1", nil), Viewer{}},
+		{"header from proxy network", request("", "10.1.2.3", user), Viewer{Authenticated: true, Name: "bob"}},
+		{"header from proxy address", request("", "192.168.1.5", user), Viewer{Authenticated: true, Name: "bob"}},
+		{"header from ipv6 proxy", request("", "::1", user), Viewer{Authenticated: true, Name: "bob"}},
+		{"header from elsewhere", request("", "192.168.1.6", user), Viewer{}},
+		{"header without address", request("", "", user), Viewer{}},
+		{"blank header falls back", request("Bearer t0ken", "10.1.2.3", map[string]string{"X-Forwarded-User": " "}), Viewer{Authenticated: true, Name: "token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Identify(tt.req); got != tt.want {
+				t.Errorf("Identify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyIgnoresHeaderWithoutTrustedProxies(t *testing.T) {
+	a := NewViewerAuth(ViewerConfig{TrustedHeader: "X-Forwarded-User"})
+	user := map[string]string{"X-Forwarded-User": "bob"}
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "203.0.113.1"} {
+		if got := a.Identify(request("", ip, user)); got.Authenticated {
+			t.Errorf("Identify() from %s = %+v, want anonymous", ip, got)
+		}
+	}
+}
+
+func TestCanView(t *testing.T) {
+	a := NewViewerAuth(ViewerConfig{
+		Visibility: VisibilityPrivate,
+		Apps:       map[string]AppRule{"3000": {Visibility: VisibilityPublic}},
+	})
+	signedIn := Viewer{Authenticated: true, Name: "alice"}
+
+	tests := []struct {
+		app    string
+		viewer Viewer
+		want   bool
+	}{
+		{"4000", Viewer{}, false},
+		{"4000", signedIn, true},
+		{"3000", Viewer{}, true},
+	}
+	for _, tt := range tests {
+		if got := a.CanView(tt.app, tt.viewer); got != tt.want {
+			t.Errorf("CanView(%q, %+v) = %v, want %v", tt.app, tt.viewer, got, tt.want)
+		}
+	}
+}
+
+func TestPrivate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ViewerConfig
+		want bool
+	}{
+		{"default", ViewerConfig{}, false},
+		{"private by default", ViewerConfig{Visibility: VisibilityPrivate}, true},
+		{"one private app", ViewerConfig{Apps: map[string]AppRule{"3000": {Visibility: VisibilityPrivate}}}, true},
+		{"public apps", ViewerConfig{Apps: map[string]AppRule{"3000": {Visibility: VisibilityPublic}}}, false},
+	}
+	for _, tt := range tests {
+		if got := NewViewerAuth(tt.cfg).Private(); got != tt.want {
+			t.Errorf("%s: Private() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHidden(t *testing.T) {
+	a := NewViewerAuth(ViewerConfig{
+		HiddenTags:       []string{"internal"},
+		HiddenOperations: []string{"DELETE /users/{id}"},
+		Apps:             map[string]AppRule{"3000": {HiddenOperations: []string{"GET /admin"}}},
+	})
+
+	tests := []struct {
+		name   string
+		app    string
+		viewer Viewer
+		method string
+		path   string
+		tags   []string
+		want   bool
+	}{
+		{"hidden tag", "4000", Viewer{}, "GET", "/users", []string{"Internal"}, true},
+		{"other tag", "4000", Viewer{}, "GET", "/users", []string{"users"}, false},
+		{"hidden operation", "4000", Viewer{}, "DELETE", "/users/{id}", nil, true},
+		{"hidden operation in colon style", "4000", Viewer{}, "DELETE", "/users/:userId", nil, true},
+		{"other method", "4000", Viewer{}, "GET", "/users/{id}", nil, false},
+		{"app operation", "3000", Viewer{}, "GET", "/admin", nil, true},
+		{"app operation on another app", "4000", Viewer{}, "GET", "/admin", nil, false},
+		{"authenticated", "4000", Viewer{Authenticated: true}, "GET", "/users", []string{"internal"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Hidden(tt.app, tt.viewer, tt.method, tt.path, tt.tags); got != tt.want {
+				t.Errorf("Hidden() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
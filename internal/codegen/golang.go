@@ -0,0 +1,748 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// goReserved holds the keywords and the names the generated methods use cannot name a
+// parameter.
+var goReserved = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+	"c": true, "ctx": true, "params": true, "body": true, "path": true,
+	"query": true, "header": true, "cookies": true, "out": true, "err": true,
+	"reader": true, "decodeError": true, "contentType": true, "string": true,
+	"error": true,
+}
+
+// goGen generates a Go client.
+type goGen struct {
+	spec       *openapi.Spec
+	names      namer
+	components map[string]string
+	decls      []string
+	usesTime   bool
+}
+
+// Go generates a client for the spec as a Go source file of package pkg.
+// Components become types, every operation a method of Client named after
+// its operationId, and every documented error response an error type.
+func Go(spec *openapi.Spec, pkg string) ([]byte, error) {
+	g := &goGen{spec: spec, names: namer{}, components: map[string]string{}}
+	for _, name := range runtimeNames {
+		g.names[name] = true
+	}
+
+	var schemas []string
+	if spec.Components != nil {
+		for name := range spec.Components.Schemas {
+			schemas = append(schemas, name)
+		}
+	}
+	sort.Strings(schemas)
+	for _, name := range schemas {
+		g.components[name] = g.names.unique(exportedName(name))
+	}
+	for _, name := range schemas {
+		g.componentDecl(name, spec.Components.Schemas[name])
+	}
+
+	var methods strings.Builder
+	methodNames := namer{}
+	for _, op := range operations(spec) {
+		method, err := g.method(op, methodNames)
+		if err != nil {
+			return nil, err
+		}
+		methods.WriteString(method)
+	}
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "// Code generated by atomicdocs gen go. DO NOT EDIT.\n\n")
+	title := spec.Info.Title
+	if title == "" {
+		title = "API"
+	}
+	fmt.Fprintf(&src, "// Package %s is a client for %s.\npackage %s\n\n", pkg, comment(title), pkg)
+	src.WriteString("import (\n\t\"bytes\"\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"strings\"\n")
+	if g.usesTime {
+		src.WriteString("\t\"time\"\n")
+	}
+	src.WriteString(")\n")
+	src.WriteString(goRuntime)
+	src.WriteString(g.securityOptions())
+	for _, decl := range g.decls {
+		src.WriteString("\n" + decl)
+	}
+	src.WriteString(methods.String())
+
+	out, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not parse: %w", err)
+	}
+	return out, nil
+}
+
+// componentDecl declares the type of a component schema.
+func (g *goGen) componentDecl(name string, schema types.Schema) {
+	typeName := g.components[name]
+	switch {
+	case schema.Ref != "":
+		g.decls = append(g.decls, fmt.Sprintf("// %s is the %s schema.\ntype %s = %s\n", typeName, name, typeName, g.goType(schema, typeName+"Value", false)))
+	case isObject(schema) && len(schema.Properties) > 0:
+		g.structDecl(typeName, schema, fmt.Sprintf("%s is the %s schema.", typeName, name))
+	case schema.Type == "string" && len(schema.Enum) > 0:
+		g.enumDecl(typeName, schema.Enum, fmt.Sprintf("%s is the %s schema.", typeName, name))
+	default:
+		g.decls = append(g.decls, fmt.Sprintf("// %s is the %s schema.\ntype %s %s\n", typeName, name, typeName, g.goType(schema, typeName+"Item", false)))
+	}
+}
+
+// goType returns the Go type of a schema, declaring the types of inline
+// objects and enums under hint. Parameters carry times as strings.
+func (g *goGen) goType(schema types.Schema, hint string, param bool) string {
+	if schema.Ref != "" {
+		name := schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+		if typeName, ok := g.components[name]; ok {
+			return typeName
+		}
+		return "json.RawMessage"
+	}
+	switch schema.Type {
+	case "string":
+		switch {
+		case len(schema.Enum) > 0 && !param:
+			name := g.names.unique(hint)
+			g.enumDecl(name, schema.Enum, "")
+			return name
+		case schema.Format == "date-time" && !param:
+			g.usesTime = true
+			return "time.Time"
+		case schema.Format == "byte" && !param:
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if schema.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if schema.Items == nil {
+			return "[]json.RawMessage"
+		}
+		return "[]" + g.goType(*schema.Items, hint+"Item", param)
+	}
+	if isObject(schema) {
+		if len(schema.Properties) == 0 || param {
+			return "map[string]interface{}"
+		}
+		name := g.names.unique(hint)
+		g.structDecl(name, schema, "")
+		return name
+	}
+	return "json.RawMessage"
+}
+
+func isObject(schema types.Schema) bool {
+	return schema.Type == "object" || (schema.Type == "" && len(schema.Properties) > 0)
+}
+
+// fieldType is the type of a property: optional and nullable scalars and
+// structs are pointers, so they can be left out.
+func fieldType(typ string, required, nullable bool) string {
+	if required && !nullable {
+		return typ
+	}
+	if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+		return typ
+	}
+	return "*" + typ
+}
+
+func (g *goGen) structDecl(name string, schema types.Schema, doc string) {
+	required := map[string]bool{}
+	for _, prop := range schema.Required {
+		required[prop] = true
+	}
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	var b strings.Builder
+	if doc != "" {
+		fmt.Fprintf(&b, "// %s\n", doc)
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	fields := namer{}
+	for _, prop := range props {
+		propSchema := schema.Properties[prop]
+		typ := g.goType(propSchema, name+exportedName(prop), false)
+		typ = fieldType(typ, required[prop], propSchema.Nullable)
+		// A struct cannot contain itself.
+		if typ == name {
+			typ = "*" + typ
+		}
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%s`\n", fields.unique(exportedName(prop)), typ, strconv.Quote(tag))
+	}
+	b.WriteString("}\n")
+	g.decls = append(g.decls, b.String())
+}
+
+func (g *goGen) enumDecl(name string, values []string, doc string) {
+	var b strings.Builder
+	if doc == "" {
+		doc = fmt.Sprintf("%s is one of the %s constants.", name, name)
+	}
+	fmt.Fprintf(&b, "// %s\ntype %s string\n\nconst (\n", doc, name)
+	consts := namer{}
+	for _, value := range values {
+		constName := name + exportedName(value)
+		if value == "" {
+			constName = name + "Empty"
+		}
+		fmt.Fprintf(&b, "\t%s %s = %s\n", g.names.unique(consts.unique(constName)), name, strconv.Quote(value))
+	}
+	b.WriteString(")\n")
+	g.decls = append(g.decls, b.String())
+}
+
+// goParam is a parameter of a generated method.
+type goParam struct {
+	types.Parameter
+	Field string
+	Type  string
+}
+
+func (g *goGen) method(op operation, methodNames namer) (string, error) {
+	name := methodNames.unique(operationName(op.OperationID, op.Method, op.Path))
+	g.names[name] = true
+
+	var pathParams, optional []goParam
+	args := namer{}
+	for reserved := range goReserved {
+		args[reserved] = true
+	}
+	documented := map[string]bool{}
+	for _, param := range op.Parameters {
+		p := goParam{Parameter: param, Type: g.goType(param.Schema, name+exportedName(param.Name), true)}
+		switch param.In {
+		case "path":
+			documented[param.Name] = true
+			p.Field = args.unique(lowerName(param.Name))
+			pathParams = append(pathParams, p)
+		case "query", "header", "cookie":
+			optional = append(optional, p)
+		}
+	}
+	// Undocumented path parameters are strings.
+	for _, paramName := range openapi.PathParams(op.Path) {
+		if !documented[paramName] {
+			pathParams = append(pathParams, goParam{
+				Parameter: types.Parameter{Name: paramName, In: "path", Required: true},
+				Field:     args.unique(lowerName(paramName)),
+				Type:      "string",
+			})
+		}
+	}
+
+	var b strings.Builder
+
+	paramsType := ""
+	if len(optional) > 0 {
+		paramsType = g.names.unique(name + "Params")
+		var decl strings.Builder
+		fmt.Fprintf(&decl, "// %s holds the query, header and cookie parameters of %s.\ntype %s struct {\n", paramsType, name, paramsType)
+		fields := namer{}
+		for i := range optional {
+			p := &optional[i]
+			p.Field = fields.unique(exportedName(p.Name))
+			if p.Description != "" {
+				fmt.Fprintf(&decl, "\t// %s\n", comment(p.Description))
+			}
+			fmt.Fprintf(&decl, "\t%s %s\n", p.Field, fieldType(p.Type, p.Required, false))
+		}
+		decl.WriteString("}\n")
+		g.decls = append(g.decls, decl.String())
+	}
+
+	// The request body: JSON is typed, other media types are readers.
+	bodyType, bodyContentType := "", ""
+	if op.RequestBody != nil {
+		if contentType, media, ok := requestMedia(op.RequestBody); ok {
+			bodyContentType = contentType
+			switch {
+			case isJSON(contentType):
+				bodyType = g.goType(media.Schema, name+"Request", false)
+			case strings.HasPrefix(contentType, "multipart/"):
+				// The content type carries the boundary, so the caller sets it.
+				bodyType, bodyContentType = "io.Reader", ""
+			default:
+				bodyType = "io.Reader"
+			}
+		}
+	}
+
+	// The result is the first documented success with a body.
+	resultType, resultJSON := "", false
+	var errorTypes []errorType
+	for _, status := range sortedStatuses(op.Responses) {
+		resp := op.Responses[status]
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			continue
+		}
+		contentType, media, hasBody := responseMedia(resp.Content)
+		switch {
+		case code >= 200 && code < 300 && resultType == "" && hasBody:
+			if isJSON(contentType) {
+				resultType, resultJSON = g.goType(media.Schema, name+"Response", false), true
+			} else {
+				resultType = "[]byte"
+			}
+		case code >= 400:
+			e := errorType{Code: code, Name: g.names.unique(name + statusName(code) + "Error"), Description: resp.Description}
+			if hasBody && isJSON(contentType) {
+				e.Body = g.goType(media.Schema, e.Name+"Body", false)
+			}
+			errorTypes = append(errorTypes, e)
+			g.decls = append(g.decls, e.decl(name, op))
+		}
+	}
+
+	// Doc comment and signature.
+	fmt.Fprintf(&b, "\n// %s sends %s %s.\n", name, op.Method, op.Path)
+	if op.Summary != "" || op.Description != "" {
+		b.WriteString("//\n")
+		for _, text := range []string{op.Summary, op.Description} {
+			if text != "" {
+				fmt.Fprintf(&b, "// %s\n", comment(text))
+			}
+		}
+	}
+	if len(errorTypes) > 0 {
+		b.WriteString("//\n")
+	}
+	for _, e := range errorTypes {
+		fmt.Fprintf(&b, "// A %d response is returned as a *%s.\n", e.Code, e.Name)
+	}
+	sig := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		sig = append(sig, p.Field+" "+p.Type)
+	}
+	if paramsType != "" {
+		sig = append(sig, "params *"+paramsType)
+	}
+	if bodyType != "" {
+		sig = append(sig, "body "+bodyType)
+	}
+	if bodyType == "io.Reader" && bodyContentType == "" {
+		sig = append(sig, "contentType string")
+	}
+	result := "error"
+	zero := ""
+	if resultType != "" {
+		ret := resultType
+		if g.isStruct(resultType) {
+			ret = "*" + resultType
+		}
+		result = "(" + ret + ", error)"
+		zero = "nil, "
+	}
+	fmt.Fprintf(&b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(sig, ", "), result)
+
+	// Path.
+	pathExpr, err := goPathExpr(op.Path, pathParams)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString("\tpath := " + pathExpr + "\n")
+
+	// Parameters.
+	queryArg, headerArg, cookiesArg := "nil", "nil", "nil"
+	if paramsType != "" {
+		for _, in := range []string{"query", "header", "cookie"} {
+			var ps []goParam
+			for _, p := range optional {
+				if p.In == in {
+					ps = append(ps, p)
+				}
+			}
+			if len(ps) == 0 {
+				continue
+			}
+			switch in {
+			case "query":
+				b.WriteString("\tquery := url.Values{}\n")
+				queryArg = "query"
+			case "header":
+				b.WriteString("\theader := http.Header{}\n")
+				headerArg = "header"
+			case "cookie":
+				b.WriteString("\tvar cookies []*http.Cookie\n")
+				cookiesArg = "cookies"
+			}
+			b.WriteString("\tif params != nil {\n")
+			for _, p := range ps {
+				b.WriteString(goSetParam(p))
+			}
+			b.WriteString("\t}\n")
+		}
+	}
+
+	// Body.
+	bodyArg, contentTypeArg := "nil", `""`
+	switch {
+	case bodyType == "io.Reader" && bodyContentType == "":
+		bodyArg, contentTypeArg = "body", "contentType"
+	case bodyType == "io.Reader":
+		bodyArg, contentTypeArg = "body", strconv.Quote(bodyContentType)
+	case bodyType != "":
+		fmt.Fprintf(&b, "\treader, err := jsonBody(body)\n\tif err != nil {\n\t\treturn %serr\n\t}\n", zero)
+		bodyArg, contentTypeArg = "reader", strconv.Quote(bodyContentType)
+	}
+
+	// Errors.
+	decodeArg := "nil"
+	if len(errorTypes) > 0 {
+		decodeArg = "decodeError"
+		b.WriteString("\tdecodeError := func(status int, data []byte) error {\n\t\tswitch status {\n")
+		for _, e := range errorTypes {
+			fmt.Fprintf(&b, "\t\tcase %d:\n", e.Code)
+			if e.Body != "" {
+				fmt.Fprintf(&b, "\t\t\te := &%s{}\n\t\t\t_ = json.Unmarshal(data, &e.Body)\n\t\t\treturn e\n", e.Name)
+			} else {
+				fmt.Fprintf(&b, "\t\t\treturn &%s{}\n", e.Name)
+			}
+		}
+		b.WriteString("\t\t}\n\t\treturn nil\n\t}\n")
+	}
+
+	call := fmt.Sprintf("c.do(ctx, %q, path, %s, %s, %s, %s, %s, %%s, %s)", op.Method, queryArg, headerArg, cookiesArg, bodyArg, contentTypeArg, decodeArg)
+	switch {
+	case resultType == "":
+		fmt.Fprintf(&b, "\treturn %s\n", fmt.Sprintf(call, "nil"))
+	case !resultJSON:
+		fmt.Fprintf(&b, "\tvar out []byte\n\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n", fmt.Sprintf(call, "&out"))
+	default:
+		fmt.Fprintf(&b, "\tvar out %s\n\tif err := %s; err != nil {\n\t\treturn %serr\n\t}\n", resultType, fmt.Sprintf(call, "&out"), zero)
+		if g.isStruct(resultType) {
+			b.WriteString("\treturn &out, nil\n")
+		} else {
+			b.WriteString("\treturn out, nil\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// isStruct reports whether a generated type is a struct, which methods
+// return by pointer.
+func (g *goGen) isStruct(typ string) bool {
+	for _, decl := range g.decls {
+		if strings.Contains(decl, "type "+typ+" struct {") {
+			return true
+		}
+	}
+	return false
+}
+
+// goPathExpr builds the expression of a request path, escaping the
+// parameters. Every parameter of the template needs one of params.
+func goPathExpr(path string, params []goParam) (string, error) {
+	fields := map[string]goParam{}
+	for _, p := range params {
+		fields[p.Name] = p
+	}
+	var parts []string
+	for _, part := range splitTemplate(path) {
+		if part.Param == "" {
+			parts = append(parts, strconv.Quote(part.Literal))
+			continue
+		}
+		p, ok := fields[part.Param]
+		if !ok {
+			return "", fmt.Errorf("%s: path parameter %q has no argument", path, part.Param)
+		}
+		value := p.Field
+		if p.Type != "string" {
+			value = "fmt.Sprint(" + value + ")"
+		}
+		parts = append(parts, "url.PathEscape("+value+")")
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// goSetParam adds a parameter of params to the request when it is set.
+func goSetParam(p goParam) string {
+	value := "params." + p.Field
+	var add string
+	switch p.In {
+	case "query":
+		add = "query.Add(%q, %s)"
+	case "header":
+		add = "header.Add(%q, %s)"
+	case "cookie":
+		add = "cookies = append(cookies, &http.Cookie{Name: %q, Value: %s})"
+	}
+	format := func(v string) string {
+		if strings.HasSuffix(p.Type, "string") {
+			return v
+		}
+		return "fmt.Sprint(" + v + ")"
+	}
+	typ := fieldType(p.Type, p.Required, false)
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		return fmt.Sprintf("\t\tfor _, v := range %s {\n\t\t\t%s\n\t\t}\n", value, fmt.Sprintf(add, p.Name, format("v")))
+	case strings.HasPrefix(typ, "map["):
+		return fmt.Sprintf("\t\tif %s != nil {\n\t\t\tdata, _ := json.Marshal(%s)\n\t\t\t%s\n\t\t}\n", value, value, fmt.Sprintf(add, p.Name, "string(data)"))
+	case strings.HasPrefix(typ, "*"):
+		return fmt.Sprintf("\t\tif %s != nil {\n\t\t\t%s\n\t\t}\n", value, fmt.Sprintf(add, p.Name, format("*"+value)))
+	}
+	return fmt.Sprintf("\t\t%s\n", fmt.Sprintf(add, p.Name, format(value)))
+}
+
+// errorType is the error a documented error response is returned as.
+type errorType struct {
+	Code        int
+	Name        string
+	Description string
+	Body        string
+}
+
+func (e errorType) decl(method string, op operation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s is returned by %s for a %d response", e.Name, method, e.Code)
+	if e.Description != "" {
+		fmt.Fprintf(&b, ": %s", comment(e.Description))
+	}
+	b.WriteString(".\n")
+	if e.Body != "" {
+		fmt.Fprintf(&b, "type %s struct {\n\tBody %s\n}\n\n", e.Name, e.Body)
+	} else {
+		fmt.Fprintf(&b, "type %s struct{}\n\n", e.Name)
+	}
+	fmt.Fprintf(&b, "func (e *%s) Error() string {\n\treturn %q\n}\n\n", e.Name, fmt.Sprintf("%s %s: %d %s", op.Method, op.Path, e.Code, statusText(e.Code, e.Description)))
+	fmt.Fprintf(&b, "// StatusCode returns %d.\nfunc (e *%s) StatusCode() int {\n\treturn %d\n}\n", e.Code, e.Name, e.Code)
+	return b.String()
+}
+
+// securityOptions declares an option per security scheme setting its
+// credentials on every request.
+func (g *goGen) securityOptions() string {
+	if g.spec.Components == nil {
+		return ""
+	}
+	names := make([]string, 0, len(g.spec.Components.SecuritySchemes))
+	for name := range g.spec.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		scheme := g.spec.Components.SecuritySchemes[name]
+		option := g.names.unique("With" + exportedName(name))
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			fmt.Fprintf(&b, "\n// %s sends HTTP basic credentials (%s).\nfunc %s(username, password string) Option {\n\treturn func(c *Client) {\n\t\treq, _ := http.NewRequest(\"GET\", \"/\", nil)\n\t\treq.SetBasicAuth(username, password)\n\t\tc.Header.Set(\"Authorization\", req.Header.Get(\"Authorization\"))\n\t}\n}\n", option, name, option)
+		case scheme.Type == "http" || scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+			fmt.Fprintf(&b, "\n// %s sends a bearer token (%s).\nfunc %s(token string) Option {\n\treturn func(c *Client) {\n\t\tc.Header.Set(\"Authorization\", \"Bearer \"+token)\n\t}\n}\n", option, name, option)
+		case scheme.Type == "apiKey" && scheme.In == "query":
+			fmt.Fprintf(&b, "\n// %s sends an API key in the %s query parameter (%s).\nfunc %s(key string) Option {\n\treturn func(c *Client) {\n\t\tc.Query.Set(%q, key)\n\t}\n}\n", option, scheme.Name, name, option, scheme.Name)
+		case scheme.Type == "apiKey" && scheme.In == "cookie":
+			fmt.Fprintf(&b, "\n// %s sends an API key in the %s cookie (%s).\nfunc %s(key string) Option {\n\treturn func(c *Client) {\n\t\tc.Header.Add(\"Cookie\", (&http.Cookie{Name: %q, Value: key}).String())\n\t}\n}\n", option, scheme.Name, name, option, scheme.Name)
+		case scheme.Type == "apiKey":
+			fmt.Fprintf(&b, "\n// %s sends an API key in the %s header (%s).\nfunc %s(key string) Option {\n\treturn func(c *Client) {\n\t\tc.Header.Set(%q, key)\n\t}\n}\n", option, scheme.Name, name, option, scheme.Name)
+		}
+	}
+	return b.String()
+}
+
+// runtimeNames are declared by goRuntime.
+var runtimeNames = []string{"Client", "New", "Option", "WithHTTPClient", "WithHeader", "StatusError", "ResponseError", "jsonBody"}
+
+// goRuntime is the part of every client that does not depend on the spec.
+const goRuntime = `
+// Client calls the API. Its fields may be changed before the first call.
+type Client struct {
+	// BaseURL is the server the requests are sent to, e.g.
+	// "https://api.example.com/v1".
+	BaseURL string
+	// HTTPClient sends the requests; http.DefaultClient when nil.
+	HTTPClient *http.Client
+	// Header and Query are added to every request.
+	Header http.Header
+	Query  url.Values
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// New returns a client for the API at baseURL.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{BaseURL: baseURL, Header: http.Header{}, Query: url.Values{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithHTTPClient sends the requests with client, e.g. to set timeouts or
+// a transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = client
+	}
+}
+
+// WithHeader adds a header to every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.Header.Add(key, value)
+	}
+}
+
+// StatusError is implemented by the errors of documented error responses
+// and by ResponseError.
+type StatusError interface {
+	error
+	StatusCode() int
+}
+
+// ResponseError is returned for error responses the API does not document.
+type ResponseError struct {
+	Status int
+	Body   []byte
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("unexpected response %d: %s", e.Status, strings.TrimSpace(string(e.Body)))
+}
+
+// StatusCode returns the status of the response.
+func (e *ResponseError) StatusCode() int {
+	return e.Status
+}
+
+func jsonBody(v interface{}) (io.Reader, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// do sends a request and decodes a successful response into out, a
+// *[]byte for raw bodies. decodeError turns documented error responses
+// into their error types.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, cookies []*http.Cookie, body io.Reader, contentType string, out interface{}, decodeError func(int, []byte) error) error {
+	values := url.Values{}
+	for key, vs := range c.Query {
+		values[key] = append(values[key], vs...)
+	}
+	for key, vs := range query {
+		values[key] = append(values[key], vs...)
+	}
+	target := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(values) > 0 {
+		target += "?" + values.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	for key, vs := range c.Header {
+		req.Header[key] = append([]string(nil), vs...)
+	}
+	for key, vs := range header {
+		req.Header[key] = vs
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if out != nil {
+		req.Header.Set("Accept", "application/json, */*")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if decodeError != nil {
+			if err := decodeError(resp.StatusCode, data); err != nil {
+				return err
+			}
+		}
+		return &ResponseError{Status: resp.StatusCode, Body: data}
+	}
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return nil
+}
+`
+
+// comment flattens text for a line comment.
+func comment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func statusText(code int, description string) string {
+	if description != "" {
+		return comment(description)
+	}
+	return statusName(code)
+}
+
+func isJSON(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
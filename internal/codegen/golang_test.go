@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+func loadSpec(t *testing.T, name string) *openapi.Spec {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec openapi.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	return &spec
+}
+
+// TestGoVet generates a client for the fixture and checks it with go vet
+// in a module of its own.
+func TestGoVet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go vet")
+	}
+	src, err := Go(loadSpec(t, "files.json"), "files")
+	if err != nil {
+		t.Fatalf("Go() = %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":    "module example.com/files\n\ngo 1.22\n",
+		"client.go": string(src),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go vet: %v\n%s\n\n%s", err, out, src)
+	}
+}
+
+func TestGoPaths(t *testing.T) {
+	src, err := Go(loadSpec(t, "files.json"), "files")
+	if err != nil {
+		t.Fatalf("Go() = %v", err)
+	}
+	for _, want := range []string{
+		`path := "/files/" + url.PathEscape(fileName)`,
+		`path := "/users/" + url.PathEscape(id)`,
+		`path := "/users/" + url.PathEscape(fmt.Sprint(userID)) + "/reports/" + url.PathEscape(fmt.Sprint(year)) + "." + url.PathEscape(format)`,
+		`path := "/nodes"`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated client lacks %s", want)
+		}
+	}
+}
+
+func TestGoPathExpr(t *testing.T) {
+	params := []goParam{
+		{Field: "fileName", Type: "string"},
+		{Field: "version", Type: "int64"},
+	}
+	params[0].Name = "file-name"
+	params[1].Name = "version"
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", `"/"`},
+		{"", `"/"`},
+		{"/files", `"/files"`},
+		{"/files/{file-name}", `"/files/" + url.PathEscape(fileName)`},
+		{"/files/:version/raw", `"/files/" + url.PathEscape(fmt.Sprint(version)) + "/raw"`},
+		{"/files/{file-name}.v{version}", `"/files/" + url.PathEscape(fileName) + ".v" + url.PathEscape(fmt.Sprint(version))`},
+	}
+	for _, tt := range tests {
+		got, err := goPathExpr(tt.path, params)
+		if err != nil || got != tt.want {
+			t.Errorf("goPathExpr(%q) = %s, %v, want %s", tt.path, got, err, tt.want)
+		}
+	}
+
+	if _, err := goPathExpr("/files/{missing}", params); err == nil {
+		t.Error("goPathExpr() without an argument for {missing} returned no error")
+	}
+}
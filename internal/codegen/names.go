@@ -0,0 +1,129 @@
+// Package codegen generates typed API clients from a spec.
+package codegen
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in capitals in Go names, e.g. "userId" becomes
+// "UserID".
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "OK": true, "SQL": true, "UI": true, "URI": true,
+	"URL": true, "UUID": true, "XML": true,
+}
+
+// words splits a name at punctuation and case changes: "user_id",
+// "userId" and "User ID" all give ["user", "id"]. A plural initialism
+// stays whole, so "userIDs" gives ["user", "ids"].
+func words(name string) []string {
+	var out []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			out = append(out, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			plural := i+1 < len(runes) && runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2]))
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower && !plural) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return out
+}
+
+// exportedName turns a name into an exported identifier.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		switch {
+		case initialisms[strings.ToUpper(w)]:
+			b.WriteString(strings.ToUpper(w))
+		case len(w) > 2 && strings.HasSuffix(w, "s") && initialisms[strings.ToUpper(w[:len(w)-1])]:
+			// "ids" becomes "IDs".
+			b.WriteString(strings.ToUpper(w[:len(w)-1]) + "s")
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	s := b.String()
+	if s == "" {
+		return "Value"
+	}
+	if unicode.IsDigit(rune(s[0])) {
+		return "N" + s
+	}
+	return s
+}
+
+// lowerName turns a name into an unexported identifier, e.g. for a
+// parameter.
+func lowerName(name string) string {
+	ws := words(name)
+	if len(ws) == 0 {
+		return "value"
+	}
+	s := ws[0] + exportedName(strings.Join(ws[1:], " "))
+	if len(ws) == 1 {
+		s = ws[0]
+	}
+	if unicode.IsDigit(rune(s[0])) {
+		s = "n" + s
+	}
+	return s
+}
+
+// operationName names the method of an operation: its operationId, or
+// the method and path, e.g. "GetUsersByID" for GET /users/{id}.
+func operationName(operationID, method, path string) string {
+	if operationID != "" {
+		return exportedName(operationID)
+	}
+	name := exportedName(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+		case strings.HasPrefix(segment, "{") || strings.HasPrefix(segment, ":"):
+			name += "By" + exportedName(strings.Trim(segment, "{}:"))
+		default:
+			name += exportedName(segment)
+		}
+	}
+	return name
+}
+
+// statusName names a status code, e.g. "NotFound" for 404.
+func statusName(code int) string {
+	if text := http.StatusText(code); text != "" {
+		return exportedName(text)
+	}
+	return "Status" + strconv.Itoa(code)
+}
+
+// namer hands out names that are not taken yet, numbering repeats.
+type namer map[string]bool
+
+func (n namer) unique(name string) string {
+	candidate := name
+	for i := 2; n[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	n[candidate] = true
+	return candidate
+}
@@ -0,0 +1,132 @@
+package codegen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"user_id", []string{"user", "id"}},
+		{"userId", []string{"user", "id"}},
+		{"User ID", []string{"user", "id"}},
+		{"file-name", []string{"file", "name"}},
+		{"HTTPServer", []string{"http", "server"}},
+		{"userIDs", []string{"user", "ids"}},
+		{"APIsByID", []string{"apis", "by", "id"}},
+		{"v2Users", []string{"v2", "users"}},
+		{"2fa", []string{"2fa"}},
+		{"", nil},
+		{"--", nil},
+	}
+	for _, tt := range tests {
+		if got := words(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("words(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"user", "User"},
+		{"user_id", "UserID"},
+		{"userId", "UserID"},
+		{"user ids", "UserIDs"},
+		{"userIDs", "UserIDs"},
+		{"listAPIs", "ListAPIs"},
+		{"api-url", "APIURL"},
+		{"httpStatus", "HTTPStatus"},
+		{"json", "JSON"},
+		{"ok", "OK"},
+		{"type", "Type"},
+		{"range", "Range"},
+		{"2fa", "N2fa"},
+		{"404", "N404"},
+		{"x-request-id", "XRequestID"},
+		{"", "Value"},
+		{"__", "Value"},
+	}
+	for _, tt := range tests {
+		if got := exportedName(tt.name); got != tt.want {
+			t.Errorf("exportedName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLowerName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"UserId", "userID"},
+		{"file-name", "fileName"},
+		{"ID", "id"},
+		{"URL", "url"},
+		{"type", "type"},
+		{"2fa", "n2fa"},
+		{"", "value"},
+	}
+	for _, tt := range tests {
+		if got := lowerName(tt.name); got != tt.want {
+			t.Errorf("lowerName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		operationID, method, path string
+		want                      string
+	}{
+		{"listUsers", "GET", "/users", "ListUsers"},
+		{"get_user_by_id", "GET", "/users/{id}", "GetUserByID"},
+		{"", "GET", "/users/{id}", "GetUsersByID"},
+		{"", "DELETE", "/users/:userId/keys", "DeleteUsersByUserIDKeys"},
+		{"", "POST", "/files/{file-name}", "PostFilesByFileName"},
+		{"", "GET", "/", "Get"},
+	}
+	for _, tt := range tests {
+		if got := operationName(tt.operationID, tt.method, tt.path); got != tt.want {
+			t.Errorf("operationName(%q, %q, %q) = %q, want %q", tt.operationID, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestStatusName(t *testing.T) {
+	for code, want := range map[int]string{404: "NotFound", 500: "InternalServerError", 418: "IMATeapot", 299: "Status299"} {
+		if got := statusName(code); got != want {
+			t.Errorf("statusName(%d) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+// TestReservedParameterNames checks that parameters named like Go keywords
+// or the locals of generated methods are renamed.
+func TestReservedParameterNames(t *testing.T) {
+	args := namer{}
+	for reserved := range goReserved {
+		args[reserved] = true
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"type", "type2"},
+		{"range", "range2"},
+		{"path", "path2"},
+		{"ctx", "ctx2"},
+		{"id", "id"},
+		{"ID", "id2"},
+	}
+	for _, tt := range tests {
+		if got := args.unique(lowerName(tt.name)); got != tt.want {
+			t.Errorf("parameter %q is named %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
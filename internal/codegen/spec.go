@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// operation is one operation of a spec with where it lives.
+type operation struct {
+	Method string
+	Path   string
+	*openapi.Operation
+}
+
+// operations lists the operations of a spec by path, then in the usual
+// method order, so generated clients are stable.
+func operations(spec *openapi.Spec) []operation {
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []operation
+	for _, path := range paths {
+		item := spec.Paths[path]
+		for _, method := range openapi.Methods {
+			if op := item.Operation(method); op != nil {
+				ops = append(ops, operation{Method: method, Path: path, Operation: op})
+			}
+		}
+	}
+	return ops
+}
+
+// templateParam matches a parameter of a path template, such as {id} or
+// {file-name}, which may share a segment with literal text.
+var templateParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// pathPart is a literal run of a path template or one of its parameters.
+type pathPart struct {
+	Literal string
+	Param   string
+}
+
+// splitTemplate splits a path template, in either the ":id" or the "{id}"
+// style, into literal runs and parameters.
+func splitTemplate(path string) []pathPart {
+	template := openapi.TemplatePath(path)
+	if template == "" {
+		template = "/"
+	}
+	var parts []pathPart
+	last := 0
+	for _, m := range templateParam.FindAllStringSubmatchIndex(template, -1) {
+		if m[0] > last {
+			parts = append(parts, pathPart{Literal: template[last:m[0]]})
+		}
+		parts = append(parts, pathPart{Param: template[m[2]:m[3]]})
+		last = m[1]
+	}
+	if last < len(template) {
+		parts = append(parts, pathPart{Literal: template[last:]})
+	}
+	return parts
+}
+
+// requestMedia picks the media type a request body is sent as: JSON when
+// the operation accepts it, otherwise the first one by name.
+func requestMedia(body *types.RequestBody) (string, types.MediaTypeObject, bool) {
+	return responseMedia(body.Content)
+}
+
+// responseMedia picks the media type of a body to decode, preferring JSON.
+func responseMedia(content map[string]types.MediaTypeObject) (string, types.MediaTypeObject, bool) {
+	names := make([]string, 0, len(content))
+	for name := range content {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", types.MediaTypeObject{}, false
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "application/json" || strings.HasSuffix(name, "+json") {
+			return name, content[name], true
+		}
+	}
+	return names[0], content[names[0]], true
+}
+
+// sortedStatuses lists the documented statuses in order.
+func sortedStatuses(responses map[string]types.Response) []string {
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	return statuses
+}
@@ -0,0 +1,725 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// tsReserved holds the words and the names the generated functions use
+// that cannot name a parameter.
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true, "do": true,
+	"else": true, "enum": true, "export": true, "extends": true, "false": true,
+	"finally": true, "for": true, "function": true, "if": true, "import": true,
+	"in": true, "instanceof": true, "new": true, "null": true, "return": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true,
+	"with": true, "let": true, "static": true, "yield": true, "await": true,
+	"implements": true, "interface": true, "package": true, "private": true,
+	"protected": true, "public": true, "arguments": true, "eval": true,
+	"params": true, "body": true, "init": true, "send": true, "options": true,
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsGen generates a TypeScript client.
+type tsGen struct {
+	spec       *openapi.Spec
+	names      namer
+	components map[string]string
+	decls      []string
+	// client holds the members of the Client interface, impl their
+	// implementations.
+	client []string
+	impl   []string
+}
+
+// TypeScript generates a fetch-based client for the spec as a TypeScript
+// module. Components become interfaces and types, and createClient returns
+// a Client with a function per operation, named after its operationId.
+func TypeScript(spec *openapi.Spec) []byte {
+	g := newTSGen(spec)
+	var b strings.Builder
+	g.header(&b, "ts")
+	g.types(&b)
+	b.WriteString(tsRuntime)
+	b.WriteString(g.authorize())
+	b.WriteString("\n/** createClient returns a client for the API. */\nexport function createClient(options: ClientOptions): Client {\n  const send = sender(options);\n  return {\n")
+	b.WriteString(strings.Join(g.impl, ""))
+	b.WriteString("  };\n}\n")
+	return []byte(b.String())
+}
+
+// TypeScriptTypes generates the declarations of the client TypeScript
+// generates, as a .d.ts file: for typing a client written by hand, or
+// the responses of an API called some other way.
+func TypeScriptTypes(spec *openapi.Spec) []byte {
+	g := newTSGen(spec)
+	var b strings.Builder
+	g.header(&b, "ts -types")
+	g.types(&b)
+	b.WriteString(tsRuntimeTypes)
+	b.WriteString("\n/** createClient returns a client for the API. */\nexport declare function createClient(options: ClientOptions): Client;\n")
+	return []byte(b.String())
+}
+
+func newTSGen(spec *openapi.Spec) *tsGen {
+	g := &tsGen{spec: spec, names: namer{}, components: map[string]string{}}
+	for _, name := range tsRuntimeNames {
+		g.names[name] = true
+	}
+
+	var schemas []string
+	if spec.Components != nil {
+		for name := range spec.Components.Schemas {
+			schemas = append(schemas, name)
+		}
+	}
+	sort.Strings(schemas)
+	for _, name := range schemas {
+		g.components[name] = g.names.unique(exportedName(name))
+	}
+	for _, name := range schemas {
+		g.componentDecl(name, spec.Components.Schemas[name])
+	}
+
+	functions := namer{}
+	for _, op := range operations(spec) {
+		g.operation(op, functions)
+	}
+	return g
+}
+
+func (g *tsGen) header(b *strings.Builder, command string) {
+	title := g.spec.Info.Title
+	if title == "" {
+		title = "API"
+	}
+	fmt.Fprintf(b, "// Code generated by atomicdocs gen %s. DO NOT EDIT.\n// A client for %s.\n", command, comment(title))
+}
+
+// types writes the declarations every output shares: the schemas, the
+// parameters, the credentials and the Client interface.
+func (g *tsGen) types(b *strings.Builder) {
+	for _, decl := range g.decls {
+		b.WriteString("\n" + decl)
+	}
+	b.WriteString("\n" + g.credentials())
+	b.WriteString("\n/** Client has a function per operation of the API. */\nexport interface Client {\n")
+	b.WriteString(strings.Join(g.client, "\n"))
+	b.WriteString("}\n")
+}
+
+// componentDecl declares the type of a component schema.
+func (g *tsGen) componentDecl(name string, schema types.Schema) {
+	typeName := g.components[name]
+	doc := fmt.Sprintf("/** %s is the %s schema. */\n", typeName, jsdoc(name))
+	if schema.Ref == "" && isObject(schema) && len(schema.Properties) > 0 && !schema.Nullable {
+		g.decls = append(g.decls, doc+"export interface "+typeName+" "+g.tsType(schema, "")+"\n")
+		return
+	}
+	g.decls = append(g.decls, doc+"export type "+typeName+" = "+g.tsType(schema, "")+";\n")
+}
+
+// tsType returns the TypeScript type of a schema. Inline objects are
+// written out, indented by indent.
+func (g *tsGen) tsType(schema types.Schema, indent string) string {
+	typ := g.baseType(schema, indent)
+	if schema.Nullable && typ != "unknown" {
+		typ += " | null"
+	}
+	return typ
+}
+
+func (g *tsGen) baseType(schema types.Schema, indent string) string {
+	if schema.Ref != "" {
+		name := schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+		if typeName, ok := g.components[name]; ok {
+			return typeName
+		}
+		return "unknown"
+	}
+	switch schema.Type {
+	case "string":
+		if len(schema.Enum) > 0 {
+			values := make([]string, len(schema.Enum))
+			for i, value := range schema.Enum {
+				values[i] = tsString(value)
+			}
+			return strings.Join(values, " | ")
+		}
+		if schema.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		if schema.Items == nil {
+			return "unknown[]"
+		}
+		item := g.tsType(*schema.Items, indent)
+		if strings.Contains(item, " ") && !strings.HasPrefix(item, "{") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	}
+	if isObject(schema) {
+		if len(schema.Properties) == 0 {
+			return "Record<string, unknown>"
+		}
+		return g.objectType(schema, indent)
+	}
+	return "unknown"
+}
+
+// namedType is the type of a schema an operation sends or receives:
+// inline objects, and arrays of them, are declared as interfaces named
+// after hint, so signatures stay on one line. doc says what the schema
+// is, e.g. "the request body of createUser".
+func (g *tsGen) namedType(schema types.Schema, hint, doc string) string {
+	switch {
+	case schema.Ref == "" && isObject(schema) && len(schema.Properties) > 0:
+		name := g.names.unique(hint)
+		g.decls = append(g.decls, fmt.Sprintf("/** %s is %s. */\nexport interface %s %s\n", name, doc, name, g.objectType(schema, "")))
+		if schema.Nullable {
+			return name + " | null"
+		}
+		return name
+	case schema.Ref == "" && schema.Type == "array" && schema.Items != nil && !schema.Nullable:
+		item := g.namedType(*schema.Items, hint+"Item", "an item of "+doc)
+		if strings.Contains(item, " ") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	}
+	return g.tsType(schema, "")
+}
+
+// objectType writes out an object type, a property per line.
+func (g *tsGen) objectType(schema types.Schema, indent string) string {
+	required := map[string]bool{}
+	for _, prop := range schema.Required {
+		required[prop] = true
+	}
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, prop := range props {
+		optional := "?"
+		if required[prop] {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsKey(prop), optional, g.tsType(schema.Properties[prop], indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// tsParam is a parameter of a generated function.
+type tsParam struct {
+	types.Parameter
+	Arg  string
+	Type string
+}
+
+func (g *tsGen) operation(op operation, functions namer) {
+	name := functions.unique(lowerName(operationName(op.OperationID, op.Method, op.Path)))
+	typeName := exportedName(name)
+
+	args := namer{}
+	for reserved := range tsReserved {
+		args[reserved] = true
+	}
+	var pathParams, optional []tsParam
+	documented := map[string]bool{}
+	for _, param := range op.Parameters {
+		p := tsParam{Parameter: param, Type: g.namedType(param.Schema, typeName+exportedName(param.Name), "the "+jsdoc(param.Name)+" parameter of "+name)}
+		switch param.In {
+		case "path":
+			if documented[param.Name] {
+				continue
+			}
+			documented[param.Name] = true
+			p.Arg = args.unique(lowerName(param.Name))
+			pathParams = append(pathParams, p)
+		case "query", "header", "cookie":
+			optional = append(optional, p)
+		}
+	}
+	// Undocumented path parameters are strings.
+	for _, paramName := range openapi.PathParams(op.Path) {
+		if !documented[paramName] {
+			pathParams = append(pathParams, tsParam{
+				Parameter: types.Parameter{Name: paramName, In: "path", Required: true},
+				Arg:       args.unique(lowerName(paramName)),
+				Type:      "string",
+			})
+		}
+	}
+
+	// The query, header and cookie parameters are properties of one
+	// object, named as the API names them. A name used twice keeps the
+	// first parameter.
+	paramsType, paramsRequired := "", false
+	if len(optional) > 0 {
+		paramsType = g.names.unique(typeName + "Params")
+		var decl strings.Builder
+		fmt.Fprintf(&decl, "/** %s holds the query, header and cookie parameters of %s. */\nexport interface %s {\n", paramsType, name, paramsType)
+		seen := map[string]bool{}
+		kept := optional[:0]
+		for _, p := range optional {
+			if seen[p.Name] {
+				continue
+			}
+			seen[p.Name] = true
+			kept = append(kept, p)
+			if p.Description != "" {
+				fmt.Fprintf(&decl, "  /** %s */\n", jsdoc(p.Description))
+			}
+			mark := "?"
+			if p.Required {
+				mark, paramsRequired = "", true
+			}
+			fmt.Fprintf(&decl, "  %s%s: %s;\n", tsKey(p.Name), mark, p.Type)
+		}
+		optional = kept
+		decl.WriteString("}\n")
+		g.decls = append(g.decls, decl.String())
+	}
+
+	// The request body: JSON and forms are typed, other media types are
+	// sent as they are given.
+	bodyType, bodyKind, bodyContentType, bodyRequired := "", "", "", false
+	if op.RequestBody != nil {
+		if contentType, media, ok := requestMedia(op.RequestBody); ok {
+			bodyContentType, bodyRequired = contentType, op.RequestBody.Required
+			switch {
+			case isJSON(contentType):
+				bodyType, bodyKind = g.namedType(media.Schema, typeName+"Request", "the request body of "+name), "json"
+			case contentType == "application/x-www-form-urlencoded":
+				bodyType, bodyKind = g.namedType(media.Schema, typeName+"Request", "the request body of "+name), "form"
+			case strings.HasPrefix(contentType, "multipart/"):
+				// fetch sets the content type with its boundary.
+				bodyType, bodyKind, bodyContentType = "FormData", "raw", ""
+			default:
+				bodyType, bodyKind = "BodyInit", "raw"
+			}
+		}
+	}
+
+	// The result is the first documented success with a body.
+	resultType, accept := "void", "none"
+	var throws []string
+	for _, status := range sortedStatuses(op.Responses) {
+		resp := op.Responses[status]
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			continue
+		}
+		contentType, media, hasBody := responseMedia(resp.Content)
+		switch {
+		case code >= 200 && code < 300 && accept == "none" && hasBody:
+			switch {
+			case isJSON(contentType):
+				resultType, accept = g.namedType(media.Schema, typeName+"Response", "the response of "+name), "json"
+			case strings.HasPrefix(contentType, "text/"):
+				resultType, accept = "string", "text"
+			default:
+				resultType, accept = "Blob", "blob"
+			}
+		case code >= 400:
+			errorBody := "unknown"
+			if hasBody && isJSON(contentType) {
+				errorBody = g.namedType(media.Schema, typeName+statusName(code)+"Error", fmt.Sprintf("the body of a %d response of %s", code, name))
+			}
+			throw := fmt.Sprintf("@throws {ApiError<%s>} %d", errorBody, code)
+			if resp.Description != "" {
+				throw += ": " + jsdoc(resp.Description)
+			}
+			throws = append(throws, throw)
+		}
+	}
+
+	// The member of the Client interface.
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "  /**\n   * %s sends %s %s.\n", name, op.Method, jsdoc(op.Path))
+	for _, text := range []string{op.Summary, op.Description} {
+		if text != "" {
+			fmt.Fprintf(&doc, "   *\n   * %s\n", jsdoc(text))
+		}
+	}
+	if len(throws) > 0 {
+		doc.WriteString("   *\n")
+	}
+	for _, throw := range throws {
+		fmt.Fprintf(&doc, "   * %s\n", throw)
+	}
+	doc.WriteString("   */\n")
+
+	var sig, argNames []string
+	for _, p := range pathParams {
+		sig = append(sig, p.Arg+": "+p.Type)
+		argNames = append(argNames, p.Arg)
+	}
+	if bodyType != "" {
+		switch {
+		case bodyRequired:
+			sig = append(sig, "body: "+bodyType)
+		case paramsRequired:
+			sig = append(sig, "body: "+bodyType+" | undefined")
+		default:
+			sig = append(sig, "body?: "+bodyType)
+		}
+		argNames = append(argNames, "body")
+	}
+	if paramsType != "" {
+		if paramsRequired {
+			sig = append(sig, "params: "+paramsType)
+		} else {
+			sig = append(sig, "params?: "+paramsType)
+		}
+		argNames = append(argNames, "params")
+	}
+	sig = append(sig, "init?: RequestInit")
+	argNames = append(argNames, "init")
+	g.client = append(g.client, fmt.Sprintf("%s  %s(%s): Promise<%s>;\n", doc.String(), name, strings.Join(sig, ", "), resultType))
+
+	// The implementation.
+	var b strings.Builder
+	fmt.Fprintf(&b, "    %s(%s) {\n", name, strings.Join(argNames, ", "))
+	fmt.Fprintf(&b, "      return send<%s>({\n", resultType)
+	fmt.Fprintf(&b, "        method: %s,\n", tsString(op.Method))
+	fmt.Fprintf(&b, "        path: %s,\n", tsPathExpr(op.Path, pathParams))
+	for _, in := range [][2]string{{"query", "queries"}, {"header", "headers"}, {"cookie", "cookies"}} {
+		var props []string
+		for _, p := range optional {
+			if p.In == in[0] {
+				props = append(props, tsKey(p.Name)+": params"+tsAccess(p.Name, paramsRequired))
+			}
+		}
+		if len(props) > 0 {
+			fmt.Fprintf(&b, "        %s: { %s },\n", in[1], strings.Join(props, ", "))
+		}
+	}
+	if bodyKind != "" {
+		fmt.Fprintf(&b, "        body,\n        bodyKind: %s,\n", tsString(bodyKind))
+		if bodyContentType != "" {
+			fmt.Fprintf(&b, "        contentType: %s,\n", tsString(bodyContentType))
+		}
+	}
+	fmt.Fprintf(&b, "        accept: %s,\n", tsString(accept))
+	b.WriteString("      }, init);\n    },\n")
+	g.impl = append(g.impl, b.String())
+}
+
+// credentials declares the credentials of every security scheme.
+func (g *tsGen) credentials() string {
+	var b strings.Builder
+	b.WriteString("/** Credentials holds the credentials to send, by security scheme. */\nexport interface Credentials {\n")
+	for _, name := range g.schemes() {
+		scheme := g.spec.Components.SecuritySchemes[name]
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			fmt.Fprintf(&b, "  /** HTTP basic credentials. */\n  %s?: { username: string; password: string };\n", tsKey(name))
+		case scheme.Type == "http" || scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+			fmt.Fprintf(&b, "  /** A bearer token. */\n  %s?: string;\n", tsKey(name))
+		case scheme.Type == "apiKey" && scheme.In == "query":
+			fmt.Fprintf(&b, "  /** An API key, sent in the %s query parameter. */\n  %s?: string;\n", jsdoc(scheme.Name), tsKey(name))
+		case scheme.Type == "apiKey":
+			fmt.Fprintf(&b, "  /** An API key, sent in the %s %s. */\n  %s?: string;\n", jsdoc(scheme.Name), scheme.In, tsKey(name))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// authorize adds the credentials that are set to a request.
+func (g *tsGen) authorize() string {
+	var b strings.Builder
+	// Parameters no scheme uses are prefixed with an underscore, which
+	// keeps noUnusedParameters quiet.
+	used := map[string]bool{}
+	for _, name := range g.schemes() {
+		scheme := g.spec.Components.SecuritySchemes[name]
+		value := "auth" + tsAccess(name, true)
+		var target, set string
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			target, set = "headers", fmt.Sprintf(`set("Authorization", "Basic " + btoa(%s.username + ":" + %s.password))`, value, value)
+		case scheme.Type == "http" || scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+			target, set = "headers", fmt.Sprintf(`set("Authorization", "Bearer " + %s)`, value)
+		case scheme.Type == "apiKey" && scheme.In == "query":
+			target, set = "query", fmt.Sprintf("set(%s, %s)", tsString(scheme.Name), value)
+		case scheme.Type == "apiKey" && scheme.In == "cookie":
+			target, set = "cookies", fmt.Sprintf("push(%s + \"=\" + encodeURIComponent(%s))", tsString(scheme.Name), value)
+		case scheme.Type == "apiKey":
+			target, set = "headers", fmt.Sprintf("set(%s, %s)", tsString(scheme.Name), value)
+		default:
+			continue
+		}
+		used["auth"], used[target] = true, true
+		fmt.Fprintf(&b, "  if (%s !== undefined) {\n    %s.%s;\n  }\n", value, target, set)
+	}
+	params := [][2]string{{"auth", "Credentials"}, {"headers", "Headers"}, {"query", "URLSearchParams"}, {"cookies", "string[]"}}
+	decls := make([]string, len(params))
+	for i, param := range params {
+		decls[i] = param[0] + ": " + param[1]
+		if !used[param[0]] {
+			decls[i] = "_" + decls[i]
+		}
+	}
+	return "\nfunction authorize(" + strings.Join(decls, ", ") + "): void {\n" + b.String() + "}\n"
+}
+
+func (g *tsGen) schemes() []string {
+	if g.spec.Components == nil {
+		return nil
+	}
+	names := make([]string, 0, len(g.spec.Components.SecuritySchemes))
+	for name := range g.spec.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tsPathExpr builds the expression of a request path, encoding the
+// parameters.
+func tsPathExpr(path string, params []tsParam) string {
+	args := map[string]string{}
+	for _, p := range params {
+		args[p.Name] = p.Arg
+	}
+	var parts []string
+	for _, part := range splitTemplate(path) {
+		if part.Param == "" {
+			parts = append(parts, tsString(part.Literal))
+		} else {
+			parts = append(parts, "encodeURIComponent(String("+args[part.Param]+"))")
+		}
+	}
+	return strings.Join(parts, " + ")
+}
+
+// tsString quotes a string literal.
+func tsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// tsKey writes a property name, quoted unless it is an identifier.
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return tsString(name)
+}
+
+// tsAccess reads a property of an object that may be undefined unless
+// required is set.
+func tsAccess(name string, required bool) string {
+	switch {
+	case tsIdentifier.MatchString(name) && required:
+		return "." + name
+	case tsIdentifier.MatchString(name):
+		return "?." + name
+	case required:
+		return "[" + tsString(name) + "]"
+	}
+	return "?.[" + tsString(name) + "]"
+}
+
+// jsdoc flattens text for a doc comment.
+func jsdoc(text string) string {
+	return strings.ReplaceAll(comment(text), "*/", "*\\/")
+}
+
+// tsRuntimeNames are declared by tsRuntime.
+var tsRuntimeNames = []string{"Client", "ClientOptions", "Credentials", "ApiError", "Call", "createClient"}
+
+// tsOptions declares the options of createClient.
+const tsOptions = `
+/** ClientOptions configure createClient. */
+export interface ClientOptions {
+  /** The server the requests are sent to, e.g. "https://api.example.com/v1". */
+  baseUrl: string;
+  /** Sends the requests; the global fetch when unset. */
+  fetch?: typeof fetch;
+  /** Headers added to every request. */
+  headers?: Record<string, string>;
+  /** Credentials added to every request. */
+  auth?: Credentials;
+}
+`
+
+// tsRuntimeTypes declares what tsRuntime exports.
+const tsRuntimeTypes = tsOptions + `
+/**
+ * ApiError is thrown for responses with an error status. Its body is the
+ * response decoded as JSON, or its text.
+ */
+export declare class ApiError<B = unknown> extends Error {
+  readonly status: number;
+  readonly body: B;
+  readonly response: Response;
+  constructor(response: Response, body: B);
+}
+`
+
+// tsRuntime is the part of every client that does not depend on the spec.
+const tsRuntime = tsOptions + `
+/**
+ * ApiError is thrown for responses with an error status. Its body is the
+ * response decoded as JSON, or its text.
+ */
+export class ApiError<B = unknown> extends Error {
+  readonly status: number;
+  readonly body: B;
+  readonly response: Response;
+
+  constructor(response: Response, body: B) {
+    super(` + "`${response.status} ${response.statusText}`" + `.trim());
+    this.name = "ApiError";
+    // Keeps instanceof working when compiled to ES5.
+    Object.setPrototypeOf(this, new.target.prototype);
+    this.status = response.status;
+    this.body = body;
+    this.response = response;
+  }
+}
+
+interface Call {
+  method: string;
+  path: string;
+  queries?: Record<string, unknown>;
+  headers?: Record<string, unknown>;
+  cookies?: Record<string, unknown>;
+  body?: unknown;
+  bodyKind?: "json" | "form" | "raw";
+  contentType?: string;
+  accept: "json" | "text" | "blob" | "none";
+}
+
+// values serialises a parameter: arrays repeat it, objects are sent as
+// JSON and unset values are left out.
+function values(value: unknown): string[] {
+  if (value === undefined || value === null) {
+    return [];
+  }
+  if (Array.isArray(value)) {
+    return value.reduce<string[]>((out, item) => out.concat(values(item)), []);
+  }
+  if (value instanceof Date) {
+    return [value.toISOString()];
+  }
+  if (typeof value === "object") {
+    return [JSON.stringify(value)];
+  }
+  return [String(value)];
+}
+
+async function decode(response: Response): Promise<unknown> {
+  const text = await response.text();
+  if (text !== "" && /json/.test(response.headers.get("Content-Type") ?? "")) {
+    try {
+      return JSON.parse(text);
+    } catch {
+      return text;
+    }
+  }
+  return text;
+}
+
+function sender(options: ClientOptions) {
+  // fetch is called detached, as browsers only allow it on window.
+  const doFetch = options.fetch ?? fetch;
+  return async function send<T>(call: Call, init?: RequestInit): Promise<T> {
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(call.queries ?? {})) {
+      for (const v of values(value)) {
+        query.append(name, v);
+      }
+    }
+    const headers = new Headers(options.headers);
+    for (const [name, value] of Object.entries(call.headers ?? {})) {
+      const vs = values(value);
+      if (vs.length > 0) {
+        headers.set(name, vs.join(","));
+      }
+    }
+    const cookies: string[] = [];
+    for (const [name, value] of Object.entries(call.cookies ?? {})) {
+      const vs = values(value);
+      if (vs.length > 0) {
+        cookies.push(name + "=" + encodeURIComponent(vs.join(",")));
+      }
+    }
+    authorize(options.auth ?? {}, headers, query, cookies);
+    if (cookies.length > 0) {
+      headers.set("Cookie", cookies.join("; "));
+    }
+    if (call.accept === "json") {
+      headers.set("Accept", "application/json");
+    }
+
+    let body: BodyInit | null = null;
+    if (call.body !== undefined) {
+      switch (call.bodyKind) {
+        case "json":
+          body = JSON.stringify(call.body);
+          break;
+        case "form": {
+          const form = new URLSearchParams();
+          for (const [name, value] of Object.entries(call.body as Record<string, unknown>)) {
+            for (const v of values(value)) {
+              form.append(name, v);
+            }
+          }
+          body = form;
+          break;
+        }
+        default:
+          body = call.body as BodyInit;
+      }
+      if (call.contentType !== undefined) {
+        headers.set("Content-Type", call.contentType);
+      }
+    }
+    new Headers(init?.headers).forEach((value, name) => headers.set(name, value));
+
+    const search = query.toString();
+    const url = options.baseUrl.replace(/\/+$/, "") + call.path + (search === "" ? "" : "?" + search);
+    const response = await doFetch(url, { ...init, method: call.method, headers, body });
+    if (!response.ok) {
+      throw new ApiError(response, await decode(response));
+    }
+    switch (call.accept) {
+      case "json": {
+        const text = await response.text();
+        return (text === "" ? undefined : JSON.parse(text)) as T;
+      }
+      case "text":
+        return (await response.text()) as T;
+      case "blob":
+        return (await response.blob()) as T;
+    }
+    return undefined as T;
+  };
+}
+`
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/atomicdocs/internal/auth"
+	"github.com/yourusername/atomicdocs/internal/lint"
+	"github.com/yourusername/atomicdocs/internal/logging"
+	"github.com/yourusername/atomicdocs/internal/mock"
+	"github.com/yourusername/atomicdocs/internal/proxy"
+	"github.com/yourusername/atomicdocs/internal/registry"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// Config is the AtomicDocs server configuration. It is read from a JSON file
+// passed with -config; every field is optional.
+type Config struct {
+	Addr string `json:"addr,omitempty"`
+
+	// PublicURL is where browsers reach the server, used by the docs page
+	// for live reload. Defaults to the host the page was requested from.
+	PublicURL string `json:"publicUrl,omitempty"`
+
+	// Docs holds the default document metadata applied to every app. Apps
+	// can override it field by field in their registration payload.
+	Docs types.AppMeta `json:"docs,omitempty"`
+
+	// HistoryLimit caps how many registrations are kept per app.
+	HistoryLimit int `json:"historyLimit,omitempty"`
+
+	// Registration holds the tokens and secrets required to register routes.
+	Registration auth.RegistrationConfig `json:"registration,omitempty"`
+
+	// Viewer controls who can read the docs and which operations they see.
+	Viewer auth.ViewerConfig `json:"viewer,omitempty"`
+
+	// CORS is the cross-origin policy applied to every endpoint.
+	CORS CORSConfig `json:"cors,omitempty"`
+
+	// Metrics configures the Prometheus endpoint.
+	Metrics MetricsConfig `json:"metrics,omitempty"`
+
+	// Log sets the log level and format, and where registrations are
+	// captured in debug mode.
+	Log logging.Config `json:"log,omitempty"`
+
+	// Mock configures the mock server under /mock/{app}.
+	Mock MockConfig `json:"mock,omitempty"`
+
+	// Proxy limits the try-it proxy under /api/v1/proxy/{app}.
+	Proxy proxy.Config `json:"proxy,omitempty"`
+
+	// Lint sets the rules the docs page and /docs/lint check specs against.
+	Lint lint.Config `json:"lint,omitempty"`
+}
+
+// MockConfig tunes the mock server. Seed makes synthesised data differ
+// between setups while staying stable across requests; Validate rejects
+// requests that do not match the documented parameters and bodies.
+type MockConfig struct {
+	Seed     int64 `json:"seed,omitempty"`
+	Validate bool  `json:"validate,omitempty"`
+}
+
+// MetricsConfig tunes the /metrics endpoint. An app counts as stale once it
+// has not registered for StaleAfterSeconds.
+type MetricsConfig struct {
+	StaleAfterSeconds int `json:"staleAfterSeconds,omitempty"`
+}
+
+type CORSConfig struct {
+	AllowOrigins     []string `json:"allowOrigins,omitempty"`
+	AllowMethods     []string `json:"allowMethods,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty"`
+	MaxAge           int      `json:"maxAge,omitempty"`
+}
+
+func Default() *Config {
+	return &Config{
+		Addr:         ":6174",
+		HistoryLimit: registry.DefaultHistoryLimit,
+		CORS: CORSConfig{
+			AllowOrigins: []string{"*"},
+			AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowHeaders: []string{
+				"Content-Type",
+				"Authorization",
+				"X-App-Port",
+				auth.TimestampHeader,
+				auth.SignatureHeader,
+				"X-Request-ID",
+				mock.StatusHeader,
+				mock.SeedHeader,
+				mock.ValidateHeader,
+				proxy.TargetHeader,
+				proxy.AuthorizationHeader,
+			},
+			ExposeHeaders: []string{"X-Request-ID", "Server-Timing"},
+			MaxAge:        600,
+		},
+		Metrics: MetricsConfig{
+			StaleAfterSeconds: 24 * 60 * 60,
+		},
+		Proxy: proxy.Config{
+			TimeoutSeconds:   30,
+			MaxRequestBytes:  1 << 20,
+			MaxResponseBytes: 10 << 20,
+			AllowHeaders: []string{
+				"Accept",
+				"Accept-Language",
+				"Content-Type",
+				"If-Match",
+				"If-None-Match",
+				"X-API-Key",
+				"X-Request-ID",
+			},
+		},
+	}
+}
+
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := cfg.Lint.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid lint config: %w", err)
+	}
+
+	return cfg, nil
+}
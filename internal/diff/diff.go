@@ -0,0 +1,482 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// Change is a single difference between two versions of a spec.
+type Change struct {
+	Kind     string `json:"kind"`
+	Breaking bool   `json:"breaking"`
+	Method   string `json:"method,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Report lists every change found between two specs, breaking ones first.
+type Report struct {
+	Breaking    int      `json:"breaking"`
+	NonBreaking int      `json:"nonBreaking"`
+	Changes     []Change `json:"changes"`
+}
+
+func (r *Report) HasBreaking() bool {
+	return r.Breaking > 0
+}
+
+type comparer struct {
+	old, new *openapi.Spec
+	changes  []Change
+	// visiting holds the reference pairs compareSchema is inside of.
+	visiting map[string]bool
+}
+
+// Compare classifies every difference between old and new. Request-side
+// changes are breaking when they make a previously valid request invalid;
+// response-side changes are breaking when clients can no longer rely on
+// something they used to receive.
+func Compare(old, new *openapi.Spec) *Report {
+	c := &comparer{old: old, new: new, visiting: map[string]bool{}}
+
+	oldOps := operationIndex(old)
+	newOps := operationIndex(new)
+
+	for key, before := range oldOps {
+		after, ok := newOps[key]
+		if !ok {
+			c.add(Change{
+				Kind:     "endpoint-removed",
+				Breaking: true,
+				Method:   before.method,
+				Path:     before.path,
+				Message:  "endpoint was removed",
+			})
+			continue
+		}
+		c.compareOperation(before, after)
+	}
+
+	for key, after := range newOps {
+		if _, ok := oldOps[key]; !ok {
+			c.add(Change{
+				Kind:    "endpoint-added",
+				Method:  after.method,
+				Path:    after.path,
+				Message: "endpoint was added",
+			})
+		}
+	}
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		a, b := c.changes[i], c.changes[j]
+		if a.Breaking != b.Breaking {
+			return a.Breaking
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Kind < b.Kind
+	})
+
+	report := &Report{Changes: c.changes}
+	if report.Changes == nil {
+		report.Changes = []Change{}
+	}
+	for _, change := range report.Changes {
+		if change.Breaking {
+			report.Breaking++
+		} else {
+			report.NonBreaking++
+		}
+	}
+	return report
+}
+
+type operationRef struct {
+	method string
+	path   string
+	op     *openapi.Operation
+}
+
+func operationIndex(spec *openapi.Spec) map[string]operationRef {
+	index := make(map[string]operationRef)
+	for path, item := range spec.Paths {
+		for _, method := range openapi.Methods {
+			if op := item.Operation(method); op != nil {
+				index[method+" "+openapi.PathKey(path)] = operationRef{method: method, path: path, op: op}
+			}
+		}
+	}
+	return index
+}
+
+func (c *comparer) add(change Change) {
+	c.changes = append(c.changes, change)
+}
+
+func (c *comparer) compareOperation(before, after operationRef) {
+	at := func(change Change) Change {
+		change.Method = after.method
+		change.Path = after.path
+		return change
+	}
+
+	c.compareParameters(before, after, at)
+	c.compareRequestBody(before.op.RequestBody, after.op.RequestBody, at)
+	c.compareResponses(before.op.Responses, after.op.Responses, at)
+}
+
+func (c *comparer) compareParameters(before, after operationRef, at func(Change) Change) {
+	oldParams := parameterIndex(before)
+	newParams := parameterIndex(after)
+
+	for key, param := range newParams {
+		location := "parameter " + param.In + "." + param.Name
+		old, ok := oldParams[key]
+		if !ok {
+			c.add(at(Change{
+				Kind:     "parameter-added",
+				Breaking: param.Required,
+				Location: location,
+				Message:  requiredWord(param.Required) + " parameter was added",
+			}))
+			continue
+		}
+		if param.Required && !old.Required {
+			c.add(at(Change{
+				Kind:     "parameter-required",
+				Breaking: true,
+				Location: location,
+				Message:  "parameter became required",
+			}))
+		} else if !param.Required && old.Required {
+			c.add(at(Change{
+				Kind:     "parameter-optional",
+				Location: location,
+				Message:  "parameter became optional",
+			}))
+		}
+		c.compareSchema(location, old.Schema, param.Schema, true, at)
+	}
+
+	for key, param := range oldParams {
+		if _, ok := newParams[key]; !ok {
+			c.add(at(Change{
+				Kind:     "parameter-removed",
+				Location: "parameter " + param.In + "." + param.Name,
+				Message:  "parameter was removed",
+			}))
+		}
+	}
+}
+
+// parameterIndex keys parameters by location and name. Path parameters are
+// keyed by position since renaming them does not change the request.
+func parameterIndex(ref operationRef) map[string]types.Parameter {
+	index := make(map[string]types.Parameter)
+	positions := make(map[string]int)
+	for i, name := range openapi.PathParams(ref.path) {
+		positions[name] = i
+	}
+	for _, param := range ref.op.Parameters {
+		key := param.In + "." + param.Name
+		if pos, ok := positions[param.Name]; ok && param.In == "path" {
+			key = fmt.Sprintf("path.#%d", pos)
+		}
+		index[key] = param
+	}
+	return index
+}
+
+func (c *comparer) compareRequestBody(before, after *types.RequestBody, at func(Change) Change) {
+	switch {
+	case before == nil && after == nil:
+		return
+	case before == nil:
+		c.add(at(Change{
+			Kind:     "request-body-added",
+			Breaking: after.Required,
+			Location: "request body",
+			Message:  requiredWord(after.Required) + " request body was added",
+		}))
+		return
+	case after == nil:
+		c.add(at(Change{
+			Kind:     "request-body-removed",
+			Location: "request body",
+			Message:  "request body was removed",
+		}))
+		return
+	}
+
+	if after.Required && !before.Required {
+		c.add(at(Change{
+			Kind:     "request-body-required",
+			Breaking: true,
+			Location: "request body",
+			Message:  "request body became required",
+		}))
+	}
+
+	for contentType, media := range after.Content {
+		location := "request body " + contentType
+		old, ok := before.Content[contentType]
+		if !ok {
+			c.add(at(Change{
+				Kind:     "request-media-type-added",
+				Location: location,
+				Message:  "media type was added",
+			}))
+			continue
+		}
+		c.compareSchema(location, old.Schema, media.Schema, true, at)
+	}
+	for contentType := range before.Content {
+		if _, ok := after.Content[contentType]; !ok {
+			c.add(at(Change{
+				Kind:     "request-media-type-removed",
+				Breaking: true,
+				Location: "request body " + contentType,
+				Message:  "media type is no longer accepted",
+			}))
+		}
+	}
+}
+
+func (c *comparer) compareResponses(before, after map[string]types.Response, at func(Change) Change) {
+	for status, old := range before {
+		location := "response " + status
+		resp, ok := after[status]
+		if !ok {
+			c.add(at(Change{
+				Kind:     "response-removed",
+				Breaking: strings.HasPrefix(status, "2"),
+				Location: location,
+				Message:  "response status is no longer documented",
+			}))
+			continue
+		}
+		for contentType, media := range old.Content {
+			next, ok := resp.Content[contentType]
+			if !ok {
+				c.add(at(Change{
+					Kind:     "response-media-type-removed",
+					Breaking: true,
+					Location: location + " " + contentType,
+					Message:  "media type is no longer returned",
+				}))
+				continue
+			}
+			c.compareSchema(location+" "+contentType, media.Schema, next.Schema, false, at)
+		}
+	}
+	for status := range after {
+		if _, ok := before[status]; !ok {
+			c.add(at(Change{
+				Kind:     "response-added",
+				Location: "response " + status,
+				Message:  "response status was added",
+			}))
+		}
+	}
+}
+
+// compareSchema walks two schemas side by side, following references.
+// request tells whether the schema describes data the client sends, which
+// flips what counts as breaking for required fields and enums.
+func (c *comparer) compareSchema(location string, before, after types.Schema, request bool, at func(Change) Change) {
+	// A recursive schema, such as a Node whose next is a Node, is compared
+	// once for each pair of references on the way down.
+	if before.Ref != "" || after.Ref != "" {
+		pair := before.Ref + " " + after.Ref
+		if c.visiting[pair] {
+			return
+		}
+		c.visiting[pair] = true
+		defer delete(c.visiting, pair)
+	}
+	before, after = c.old.ResolveSchema(before), c.new.ResolveSchema(after)
+
+	oldType, newType := schemaType(before), schemaType(after)
+	switch {
+	case oldType == newType:
+	case oldType == "":
+		c.add(at(Change{
+			Kind:     "type-added",
+			Breaking: request,
+			Location: location,
+			Message:  "values are now restricted to " + newType,
+		}))
+	case newType == "":
+		c.add(at(Change{
+			Kind:     "type-removed",
+			Breaking: !request,
+			Location: location,
+			Message:  "values are no longer restricted to " + oldType,
+		}))
+	default:
+		c.add(at(Change{
+			Kind:     "type-changed",
+			Breaking: true,
+			Location: location,
+			Message:  fmt.Sprintf("type changed from %s to %s", oldType, newType),
+		}))
+		return
+	}
+	if before.Format != after.Format && before.Format != "" {
+		c.add(at(Change{
+			Kind:     "format-changed",
+			Breaking: true,
+			Location: location,
+			Message:  fmt.Sprintf("format changed from %q to %q", before.Format, after.Format),
+		}))
+	}
+
+	c.compareEnum(location, before.Enum, after.Enum, request, at)
+
+	oldRequired := stringSet(before.Required)
+	newRequired := stringSet(after.Required)
+
+	for name, prop := range after.Properties {
+		propLocation := location + "." + name
+		old, ok := before.Properties[name]
+		if !ok {
+			breaking := request && newRequired[name]
+			c.add(at(Change{
+				Kind:     "property-added",
+				Breaking: breaking,
+				Location: propLocation,
+				Message:  requiredWord(breaking) + " property was added",
+			}))
+			continue
+		}
+		if request && newRequired[name] && !oldRequired[name] {
+			c.add(at(Change{
+				Kind:     "property-required",
+				Breaking: true,
+				Location: propLocation,
+				Message:  "property became required",
+			}))
+		}
+		if !request && oldRequired[name] && !newRequired[name] {
+			c.add(at(Change{
+				Kind:     "property-optional",
+				Breaking: true,
+				Location: propLocation,
+				Message:  "property is no longer always returned",
+			}))
+		}
+		c.compareSchema(propLocation, old, prop, request, at)
+	}
+
+	for name := range before.Properties {
+		if _, ok := after.Properties[name]; !ok {
+			c.add(at(Change{
+				Kind:     "property-removed",
+				Breaking: !request,
+				Location: location + "." + name,
+				Message:  "property was removed",
+			}))
+		}
+	}
+
+	if before.Items != nil && after.Items != nil {
+		c.compareSchema(location+"[]", *before.Items, *after.Items, request, at)
+	}
+}
+
+func (c *comparer) compareEnum(location string, before, after []string, request bool, at func(Change) Change) {
+	if len(before) == 0 && len(after) == 0 {
+		return
+	}
+	if len(after) > 0 && len(before) == 0 {
+		c.add(at(Change{
+			Kind:     "enum-added",
+			Breaking: request,
+			Location: location,
+			Message:  "values are now restricted to an enum",
+		}))
+		return
+	}
+	if len(after) == 0 {
+		c.add(at(Change{
+			Kind:     "enum-removed",
+			Breaking: !request,
+			Location: location,
+			Message:  "values are no longer restricted to an enum",
+		}))
+		return
+	}
+
+	oldValues := stringSet(before)
+	newValues := stringSet(after)
+
+	var removed, added []string
+	for _, v := range before {
+		if !newValues[v] {
+			removed = append(removed, v)
+		}
+	}
+	for _, v := range after {
+		if !oldValues[v] {
+			added = append(added, v)
+		}
+	}
+
+	if len(removed) > 0 {
+		c.add(at(Change{
+			Kind:     "enum-narrowed",
+			Breaking: request,
+			Location: location,
+			Message:  "enum values removed: " + strings.Join(removed, ", "),
+		}))
+	}
+	if len(added) > 0 {
+		c.add(at(Change{
+			Kind:     "enum-widened",
+			Location: location,
+			Message:  "enum values added: " + strings.Join(added, ", "),
+		}))
+	}
+}
+
+// schemaType returns the type of a resolved schema, inferring object and
+// array for schemas that only give properties or items.
+func schemaType(schema types.Schema) string {
+	switch {
+	case schema.Type != "":
+		return schema.Type
+	case len(schema.Properties) > 0:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func requiredWord(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
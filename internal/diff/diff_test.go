@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// spec returns a spec with one operation whose request body and 200
+// response use the given schemas.
+func spec(request, response types.Schema) *openapi.Spec {
+	return &openapi.Spec{
+		Paths: map[string]openapi.PathItem{
+			"/users": {Post: &openapi.Operation{
+				RequestBody: &types.RequestBody{Content: map[string]types.MediaTypeObject{
+					"application/json": {Schema: request},
+				}},
+				Responses: map[string]types.Response{
+					"200": {Content: map[string]types.MediaTypeObject{
+						"application/json": {Schema: response},
+					}},
+				},
+			}},
+		},
+	}
+}
+
+func TestCompareSchema(t *testing.T) {
+	str := types.Schema{Type: "string"}
+	untyped := types.Schema{}
+	status := types.Schema{Type: "string", Enum: []string{"active", "banned"}}
+	active := types.Schema{Type: "string", Enum: []string{"active"}}
+	object := types.Schema{Type: "object", Properties: map[string]types.Schema{"id": {Type: "string"}}}
+	required := types.Schema{Type: "object", Properties: map[string]types.Schema{"id": {Type: "string"}}, Required: []string{"id"}}
+
+	tests := []struct {
+		name          string
+		before, after types.Schema
+		request       bool
+		kind          string
+		breaking      bool
+	}{
+		{"type changed in request", str, types.Schema{Type: "integer"}, true, "type-changed", true},
+		{"type changed in response", str, types.Schema{Type: "integer"}, false, "type-changed", true},
+		{"type added in request", untyped, str, true, "type-added", true},
+		{"type added in response", untyped, str, false, "type-added", false},
+		{"type removed in request", str, untyped, true, "type-removed", false},
+		{"type removed in response", str, untyped, false, "type-removed", true},
+		{"format changed", types.Schema{Type: "string", Format: "uuid"}, str, true, "format-changed", true},
+		{"enum added in request", str, status, true, "enum-added", true},
+		{"enum added in response", str, status, false, "enum-added", false},
+		{"enum removed in request", status, str, true, "enum-removed", false},
+		{"enum removed in response", status, str, false, "enum-removed", true},
+		{"enum narrowed in request", status, active, true, "enum-narrowed", true},
+		{"enum narrowed in response", status, active, false, "enum-narrowed", false},
+		{"enum widened in request", active, status, true, "enum-widened", false},
+		{"enum widened in response", active, status, false, "enum-widened", false},
+		{"property required in request", object, required, true, "property-required", true},
+		{"property optional in response", required, object, false, "property-optional", true},
+		{"property removed in request", object, types.Schema{Type: "object"}, true, "property-removed", false},
+		{"property removed in response", object, types.Schema{Type: "object"}, false, "property-removed", true},
+		{"implicit object typed", types.Schema{Properties: object.Properties}, object, true, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var old, new *openapi.Spec
+			if tt.request {
+				old, new = spec(tt.before, str), spec(tt.after, str)
+			} else {
+				old, new = spec(str, tt.before), spec(str, tt.after)
+			}
+			report := Compare(old, new)
+			if tt.kind == "" {
+				if len(report.Changes) != 0 {
+					t.Fatalf("Compare() = %+v, want no changes", report.Changes)
+				}
+				return
+			}
+			if len(report.Changes) != 1 {
+				t.Fatalf("Compare() = %+v, want one %s change", report.Changes, tt.kind)
+			}
+			if got := report.Changes[0]; got.Kind != tt.kind || got.Breaking != tt.breaking {
+				t.Errorf("Compare() = %s (breaking %v), want %s (breaking %v)", got.Kind, got.Breaking, tt.kind, tt.breaking)
+			}
+		})
+	}
+}
+
+func TestCompareRecursiveSchema(t *testing.T) {
+	node := types.Schema{Type: "object", Properties: map[string]types.Schema{
+		"next": {Ref: "#/components/schemas/Node"},
+	}}
+	ref := types.Schema{Ref: "#/components/schemas/Node"}
+	old, new := spec(ref, ref), spec(ref, ref)
+	old.Components = &openapi.Components{Schemas: map[string]types.Schema{"Node": node}}
+	new.Components = &openapi.Components{Schemas: map[string]types.Schema{"Node": node}}
+
+	if report := Compare(old, new); len(report.Changes) != 0 {
+		t.Errorf("Compare() = %+v, want no changes", report.Changes)
+	}
+}
@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the report as a markdown document suitable for PR
+// comments and changelogs.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## API changes\n\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d breaking, %d non-breaking.\n", r.Breaking, r.NonBreaking)
+
+	writeSection := func(title string, breaking bool) {
+		var rows []Change
+		for _, change := range r.Changes {
+			if change.Breaking == breaking {
+				rows = append(rows, change)
+			}
+		}
+		if len(rows) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", title)
+		b.WriteString("| Endpoint | Location | Change |\n")
+		b.WriteString("|----------|----------|--------|\n")
+		for _, change := range rows {
+			endpoint := strings.TrimSpace(change.Method + " " + change.Path)
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", endpoint, escapeCell(change.Location), escapeCell(change.Message))
+		}
+	}
+
+	writeSection("Breaking", true)
+	writeSection("Non-breaking", false)
+
+	return b.String()
+}
+
+// Text renders one change per line, breaking changes marked with "!".
+func (r *Report) Text() string {
+	var b strings.Builder
+	for _, change := range r.Changes {
+		mark := " "
+		if change.Breaking {
+			mark = "!"
+		}
+		endpoint := strings.TrimSpace(change.Method + " " + change.Path)
+		if change.Location != "" {
+			fmt.Fprintf(&b, "%s %s [%s] %s\n", mark, endpoint, change.Location, change.Message)
+		} else {
+			fmt.Fprintf(&b, "%s %s %s\n", mark, endpoint, change.Message)
+		}
+	}
+	fmt.Fprintf(&b, "%d breaking, %d non-breaking\n", r.Breaking, r.NonBreaking)
+	return b.String()
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// Changelog renders the report as a human-readable changelog entry under the
+// given heading, listing breaking changes before the rest.
+func (r *Report) Changelog(heading, subheading string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", heading)
+	if subheading != "" {
+		fmt.Fprintf(&b, "%s\n\n", subheading)
+	}
+	if len(r.Changes) == 0 {
+		b.WriteString("No API changes.\n")
+		return b.String()
+	}
+
+	writeList := func(title string, breaking bool) {
+		first := true
+		for _, change := range r.Changes {
+			if change.Breaking != breaking {
+				continue
+			}
+			if first {
+				fmt.Fprintf(&b, "**%s**\n\n", title)
+				first = false
+			}
+			endpoint := strings.TrimSpace(change.Method + " " + change.Path)
+			if change.Location != "" {
+				fmt.Fprintf(&b, "- `%s` %s: %s\n", endpoint, change.Location, change.Message)
+			} else {
+				fmt.Fprintf(&b, "- `%s`: %s\n", endpoint, change.Message)
+			}
+		}
+		if !first {
+			b.WriteString("\n")
+		}
+	}
+
+	writeList("Breaking changes", true)
+	writeList("Other changes", false)
+
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// Curl returns a curl command sending the example request of the operation
+// at method and path, the request the HAR export records for it. It
+// returns "" when the spec has no such operation.
+func Curl(spec *openapi.Spec, method, path string) string {
+	for _, op := range operations(spec) {
+		if op.Method == method && op.Path == path {
+			return curl(baseURL(spec), exampleRequest(spec, op))
+		}
+	}
+	return ""
+}
+
+func curl(base string, req request) string {
+	w := req.wire()
+	lines := []string{"curl"}
+	if req.Method != "GET" {
+		lines[0] += " -X " + req.Method
+	}
+	lines[0] += " " + shellQuote(w.url(base))
+	for _, f := range w.Headers {
+		// curl sets the multipart content type itself, with the boundary,
+		// and encodes basic auth credentials.
+		if f.Name == "Content-Type" && req.ContentType == "multipart/form-data" {
+			continue
+		}
+		if f.Name == "Authorization" && w.Basic != nil {
+			lines = append(lines, "-u "+shellQuote(w.Basic.Name+":"+w.Basic.Value))
+			continue
+		}
+		lines = append(lines, "-H "+shellQuote(f.Name+": "+f.Value))
+	}
+	switch {
+	case req.ContentType == "multipart/form-data":
+		for _, f := range req.formFields() {
+			if f.File {
+				lines = append(lines, "-F "+shellQuote(f.Name+"=@"+f.Name))
+			} else {
+				lines = append(lines, "-F "+shellQuote(f.Name+"="+f.Value))
+			}
+		}
+	case req.ContentType != "":
+		lines = append(lines, "-d "+shellQuote(req.bodyText()))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
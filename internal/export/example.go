@@ -0,0 +1,72 @@
+// Package export converts generated specs into the formats other API tools
+// import.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/mock"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// exampleSeed fixes the synthesised examples, so exports of the same spec
+// are identical.
+const exampleSeed = 0
+
+// paramExample returns the documented example of a parameter, or one
+// synthesised from its schema, formatted as it is sent.
+func paramExample(spec *openapi.Spec, key string, param types.Parameter) string {
+	value := param.Example
+	if value == nil {
+		value = mock.NewGenerator(exampleSeed, key+" "+param.In+" "+param.Name, spec.ResolveSchema).Value(param.Schema, param.Name)
+	}
+	return formatValue(value)
+}
+
+// formatValue formats an example the way it is sent in a parameter or form
+// field: strings as they are, arrays comma-separated and objects as JSON.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// bodyExample returns the documented example of a media type, or one
+// synthesised from its schema.
+func bodyExample(spec *openapi.Spec, key string, media types.MediaTypeObject) interface{} {
+	if media.Example != nil {
+		return media.Example
+	}
+	return mock.NewGenerator(exampleSeed, key+" body", spec.ResolveSchema).Value(media.Schema, "")
+}
+
+// baseURL is the URL of the spec's first server with its variables set to
+// their defaults.
+func baseURL(spec *openapi.Spec) string {
+	if len(spec.Servers) == 0 {
+		return "http://localhost"
+	}
+	server := spec.Servers[0]
+	url := server.URL
+	for name, variable := range server.Variables {
+		url = strings.ReplaceAll(url, "{"+name+"}", variable.Default)
+	}
+	return strings.TrimSuffix(url, "/")
+}
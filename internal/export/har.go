@@ -0,0 +1,203 @@
+package export
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/yourusername/atomicdocs/internal/mock"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// harTime is when every entry claims to have started. The requests were
+// never sent; a fixed time keeps exports of the same spec identical.
+const harTime = "1970-01-01T00:00:00.000Z"
+
+// HAR is an HTTP Archive 1.2 document.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []HARPair    `json:"cookies"`
+	Headers     []HARPair    `json:"headers"`
+	QueryString []HARPair    `json:"queryString"`
+	PostData    *HARPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Cookies     []HARPair  `json:"cookies"`
+	Headers     []HARPair  `json:"headers"`
+	Content     HARContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+}
+
+type HARPair struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type HARPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text,omitempty"`
+	Params   []HARPostParam `json:"params,omitempty"`
+}
+
+type HARPostParam struct {
+	Name     string `json:"name"`
+	Value    string `json:"value,omitempty"`
+	FileName string `json:"fileName,omitempty"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// HARFile converts a spec into an HTTP Archive with an entry per
+// operation. Requests send the example parameters and body against the
+// first server, with only the required optional parts; the responses are
+// the ones the mock server returns for them. Credentials are {{scheme}}
+// placeholders named like the Postman variables.
+func HARFile(spec *openapi.Spec) *HAR {
+	har := &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "atomicdocs"},
+		Entries: []HAREntry{},
+	}}
+	base := baseURL(spec)
+	for _, op := range operations(spec) {
+		har.Log.Entries = append(har.Log.Entries, harEntry(spec, base, exampleRequest(spec, op)))
+	}
+	return har
+}
+
+func harEntry(spec *openapi.Spec, base string, req request) HAREntry {
+	out := HARRequest{
+		Method:      req.Method,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARPair{},
+		Headers:     []HARPair{},
+		QueryString: []HARPair{},
+		HeadersSize: -1,
+	}
+	w := req.wire()
+	for _, f := range w.Query {
+		out.QueryString = append(out.QueryString, HARPair{Name: f.Name, Value: f.Value})
+	}
+	for _, f := range w.Headers {
+		pair := HARPair{Name: f.Name, Value: f.Value}
+		if f.Name == "Authorization" && w.Basic != nil {
+			pair.Comment = "base64-encode the username and password after filling them in"
+		}
+		out.Headers = append(out.Headers, pair)
+	}
+	for _, f := range w.Cookies {
+		out.Cookies = append(out.Cookies, HARPair{Name: f.Name, Value: f.Value})
+	}
+	out.URL = w.url(base)
+
+	if req.ContentType != "" {
+		out.PostData = &HARPostData{MimeType: req.ContentType}
+		if req.ContentType == "multipart/form-data" {
+			for _, f := range req.formFields() {
+				param := HARPostParam{Name: f.Name, Value: f.Value}
+				if f.File {
+					param.FileName = f.Name
+				}
+				out.PostData.Params = append(out.PostData.Params, param)
+			}
+		} else {
+			out.PostData.Text = req.bodyText()
+			if req.isForm() {
+				for _, f := range req.formFields() {
+					out.PostData.Params = append(out.PostData.Params, HARPostParam{Name: f.Name, Value: f.Value})
+				}
+			}
+		}
+		out.BodySize = len(out.PostData.Text)
+	}
+
+	return HAREntry{
+		StartedDateTime: harTime,
+		Request:         out,
+		Response:        harResponse(spec, req, w.Path),
+		Comment:         req.name(),
+	}
+}
+
+// harResponse is the mock server's response to the example request, or an
+// empty one when the operation documents no response.
+func harResponse(spec *openapi.Spec, req request, path string) HARResponse {
+	out := HARResponse{
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARPair{},
+		Headers:     []HARPair{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	params := map[string]string{}
+	for _, f := range req.PathParams {
+		params[f.Name] = f.Value
+	}
+	match := &mock.Match{Method: req.Method, Path: req.Path, Operation: req.Operation, Params: params}
+	resp, err := mock.Respond(spec, match, "", exampleSeed, path)
+	if err != nil {
+		return out
+	}
+
+	out.Status = resp.Status
+	out.StatusText = http.StatusText(resp.Status)
+	names := make([]string, 0, len(resp.Headers))
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.Headers = append(out.Headers, HARPair{Name: name, Value: resp.Headers[name]})
+	}
+	if resp.ContentType != "" {
+		out.Headers = append(out.Headers, HARPair{Name: "Content-Type", Value: resp.ContentType})
+	}
+	out.Content = HARContent{Size: len(resp.Body), MimeType: resp.ContentType, Text: string(resp.Body)}
+	out.BodySize = len(resp.Body)
+	return out
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// InsomniaExport is an Insomnia v4 export: a flat list of resources that
+// refer to their parents by ID.
+type InsomniaExport struct {
+	Type      string             `json:"_type"`
+	Format    int                `json:"__export_format"`
+	Source    string             `json:"__export_source"`
+	Resources []InsomniaResource `json:"resources"`
+}
+
+// InsomniaResource is a workspace, environment, request group or request;
+// Type tells which, and only its fields are set.
+type InsomniaResource struct {
+	ID          string  `json:"_id"`
+	Type        string  `json:"_type"`
+	ParentID    *string `json:"parentId"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+
+	// Workspaces
+	Scope string `json:"scope,omitempty"`
+
+	// Environments
+	Data map[string]string `json:"data,omitempty"`
+
+	// Requests
+	Method         string            `json:"method,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Parameters     []InsomniaPair    `json:"parameters,omitempty"`
+	Headers        []InsomniaPair    `json:"headers,omitempty"`
+	Body           *InsomniaBody     `json:"body,omitempty"`
+	Authentication map[string]string `json:"authentication,omitempty"`
+}
+
+type InsomniaPair struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+type InsomniaBody struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text,omitempty"`
+	Params   []InsomniaPair `json:"params,omitempty"`
+}
+
+// Insomnia converts a spec into an Insomnia workspace. Operations are put
+// in a request group per tag, like the Postman folders, with the example
+// path parameters filled in. The base environment holds base_url and the
+// credential variables the requests refer to.
+func Insomnia(spec *openapi.Spec) *InsomniaExport {
+	name := spec.Info.Title
+	if name == "" {
+		name = "API"
+	}
+	workspace := insomniaID("wrk", name)
+	env := map[string]string{"base_url": baseURL(spec)}
+	for _, v := range authVariables(spec) {
+		env[v.Name] = ""
+	}
+
+	out := &InsomniaExport{
+		Type:   "export",
+		Format: 4,
+		Source: "atomicdocs",
+		Resources: []InsomniaResource{
+			{ID: workspace, Type: "workspace", Name: name, Description: spec.Info.Description, Scope: "collection"},
+			{ID: insomniaID("env", name), Type: "environment", ParentID: &workspace, Name: "Base Environment", Data: env},
+		},
+	}
+
+	ops := operations(spec)
+	descriptions := map[string]string{}
+	for _, tag := range spec.Tags {
+		descriptions[tag.Name] = tag.Description
+	}
+	groups := map[string]string{}
+	for _, tag := range tagOrder(spec, ops) {
+		for _, op := range ops {
+			if len(op.Tags) > 0 && op.Tags[0] == tag {
+				groups[tag] = insomniaID("fld", workspace+" "+tag)
+				out.Resources = append(out.Resources, InsomniaResource{
+					ID:          groups[tag],
+					Type:        "request_group",
+					ParentID:    &workspace,
+					Name:        tag,
+					Description: descriptions[tag],
+				})
+				break
+			}
+		}
+	}
+
+	for _, op := range ops {
+		parent := workspace
+		if len(op.Tags) > 0 {
+			parent = groups[op.Tags[0]]
+		}
+		out.Resources = append(out.Resources, insomniaRequest(exampleRequest(spec, op), workspace, parent))
+	}
+	return out
+}
+
+func insomniaRequest(req request, workspace, parent string) InsomniaResource {
+	out := InsomniaResource{
+		ID:             insomniaID("req", workspace+" "+req.key()),
+		Type:           "request",
+		ParentID:       &parent,
+		Name:           req.name(),
+		Description:    req.Description,
+		Method:         req.Method,
+		URL:            "{{ _.base_url }}" + req.examplePath(),
+		Parameters:     []InsomniaPair{},
+		Headers:        []InsomniaPair{},
+		Authentication: insomniaAuth(req.Auth),
+	}
+	for _, f := range req.Query {
+		out.Parameters = append(out.Parameters, InsomniaPair{Name: f.Name, Value: f.Value, Description: f.Description, Disabled: !f.Required})
+	}
+	for _, f := range req.Headers {
+		out.Headers = append(out.Headers, InsomniaPair{Name: f.Name, Value: f.Value, Description: f.Description, Disabled: !f.Required})
+	}
+	if len(req.Cookies) > 0 {
+		var cookies []string
+		for _, f := range req.Cookies {
+			cookies = append(cookies, f.Name+"="+f.Value)
+		}
+		out.Headers = append(out.Headers, InsomniaPair{Name: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	if req.ContentType != "" {
+		out.Headers = append(out.Headers, InsomniaPair{Name: "Content-Type", Value: req.ContentType})
+		out.Body = &InsomniaBody{MimeType: req.ContentType}
+		if req.isForm() {
+			for _, f := range req.formFields() {
+				pair := InsomniaPair{Name: f.Name, Value: f.Value}
+				if f.File {
+					pair.Type = "file"
+				}
+				out.Body.Params = append(out.Body.Params, pair)
+			}
+		} else {
+			out.Body.Text = req.bodyText()
+		}
+	}
+	return out
+}
+
+// insomniaAuth converts a request's credentials into Insomnia's, which
+// refer to the environment variables of the security scheme. OAuth 2
+// tokens are sent as bearer tokens.
+func insomniaAuth(a *auth) map[string]string {
+	if a == nil {
+		return map[string]string{}
+	}
+	switch a.Type {
+	case "basic":
+		return map[string]string{
+			"type":     "basic",
+			"username": "{{ _." + a.Scheme + "Username }}",
+			"password": "{{ _." + a.Scheme + "Password }}",
+		}
+	case "bearer", "oauth2":
+		return map[string]string{"type": "bearer", "token": "{{ _." + a.Scheme + " }}"}
+	case "apiKey":
+		addTo := map[string]string{"header": "header", "query": "queryParams", "cookie": "cookie"}[a.In]
+		return map[string]string{"type": "apikey", "key": a.Name, "value": "{{ _." + a.Scheme + " }}", "addTo": addTo}
+	}
+	return map[string]string{"type": "none"}
+}
+
+// insomniaID derives a stable resource ID, so re-importing an export
+// updates the resources instead of duplicating them.
+func insomniaID(prefix, key string) string {
+	sum := sha256.Sum256([]byte(prefix + " " + key))
+	return prefix + "_" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"sort"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// operation is one operation of a spec with where it lives.
+type operation struct {
+	Method string
+	Path   string
+	*openapi.Operation
+}
+
+// operations lists the operations of a spec by path, then in the usual
+// method order.
+func operations(spec *openapi.Spec) []operation {
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []operation
+	for _, path := range paths {
+		item := spec.Paths[path]
+		for _, method := range openapi.Methods {
+			if op := item.Operation(method); op != nil {
+				ops = append(ops, operation{Method: method, Path: path, Operation: op})
+			}
+		}
+	}
+	return ops
+}
+
+// key identifies an operation, e.g. for seeding its examples.
+func (op operation) key() string {
+	return op.Method + " " + op.Path
+}
+
+// name is how tools list the operation: its summary, or method and path.
+func (op operation) name() string {
+	if op.Summary != "" {
+		return op.Summary
+	}
+	return op.Method + " " + op.Path
+}
+
+// tagOrder lists the tags operations are grouped by: the declared tags in
+// their order, then the others alphabetically.
+func tagOrder(spec *openapi.Spec, ops []operation) []string {
+	seen := map[string]bool{}
+	var order []string
+	for _, tag := range spec.Tags {
+		if !seen[tag.Name] {
+			seen[tag.Name] = true
+			order = append(order, tag.Name)
+		}
+	}
+	var rest []string
+	for _, op := range ops {
+		for _, tag := range op.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				rest = append(rest, tag)
+			}
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
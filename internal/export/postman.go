@@ -0,0 +1,264 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// PostmanSchema identifies the Postman collection format Postman produces.
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection is a Postman v2.1 collection.
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem is a request, or a folder of them when Item is set.
+type PostmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []PostmanItem   `json:"item,omitempty"`
+	Request     *PostmanRequest `json:"request,omitempty"`
+}
+
+type PostmanRequest struct {
+	Method      string       `json:"method"`
+	Description string       `json:"description,omitempty"`
+	Header      []PostmanKV  `json:"header"`
+	URL         PostmanURL   `json:"url"`
+	Body        *PostmanBody `json:"body,omitempty"`
+	Auth        *PostmanAuth `json:"auth,omitempty"`
+}
+
+type PostmanURL struct {
+	Raw      string      `json:"raw"`
+	Host     []string    `json:"host"`
+	Path     []string    `json:"path"`
+	Query    []PostmanKV `json:"query,omitempty"`
+	Variable []PostmanKV `json:"variable,omitempty"`
+}
+
+// PostmanKV is a header, query parameter, path variable or form field.
+type PostmanKV struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type PostmanBody struct {
+	Mode       string              `json:"mode"`
+	Raw        string              `json:"raw,omitempty"`
+	URLEncoded []PostmanKV         `json:"urlencoded,omitempty"`
+	FormData   []PostmanKV         `json:"formdata,omitempty"`
+	Options    *PostmanBodyOptions `json:"options,omitempty"`
+}
+
+type PostmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// PostmanAuth configures a request's auth. The attributes are listed under
+// the key named by Type, e.g. "bearer".
+type PostmanAuth struct {
+	Type   string
+	Params []PostmanKV
+}
+
+func (a PostmanAuth) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{"type": a.Type}
+	if a.Params != nil {
+		out[a.Type] = a.Params
+	}
+	return json.Marshal(out)
+}
+
+type PostmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Postman converts a spec into a Postman collection. Operations are put in
+// a folder per tag, under their first tag; untagged ones are listed at the
+// top level. Requests address the {{baseUrl}} collection variable and
+// reference a variable per security scheme for their credentials.
+func Postman(spec *openapi.Spec) *PostmanCollection {
+	collection := &PostmanCollection{
+		Info: PostmanInfo{
+			Name:        spec.Info.Title,
+			Description: spec.Info.Description,
+			Version:     spec.Info.Version,
+			Schema:      PostmanSchema,
+		},
+		Item: []PostmanItem{},
+		Variable: []PostmanVariable{
+			{Key: "baseUrl", Value: baseURL(spec), Type: "string"},
+		},
+	}
+	if collection.Info.Name == "" {
+		collection.Info.Name = "API"
+	}
+
+	ops := operations(spec)
+	folders := map[string]*PostmanItem{}
+	var untagged []PostmanItem
+	for _, op := range ops {
+		item := PostmanItem{Name: op.name(), Request: postmanRequest(exampleRequest(spec, op))}
+		if len(op.Tags) == 0 {
+			untagged = append(untagged, item)
+			continue
+		}
+		folder := folders[op.Tags[0]]
+		if folder == nil {
+			folder = &PostmanItem{Name: op.Tags[0], Item: []PostmanItem{}}
+			folders[op.Tags[0]] = folder
+		}
+		folder.Item = append(folder.Item, item)
+	}
+	descriptions := map[string]string{}
+	for _, tag := range spec.Tags {
+		descriptions[tag.Name] = tag.Description
+	}
+	for _, tag := range tagOrder(spec, ops) {
+		if folder := folders[tag]; folder != nil {
+			folder.Description = descriptions[tag]
+			collection.Item = append(collection.Item, *folder)
+		}
+	}
+	collection.Item = append(collection.Item, untagged...)
+
+	for _, v := range authVariables(spec) {
+		kind := "string"
+		if v.Secret {
+			kind = "secret"
+		}
+		collection.Variable = append(collection.Variable, PostmanVariable{Key: v.Name, Type: kind, Description: v.Description})
+	}
+	return collection
+}
+
+func postmanRequest(req request) *PostmanRequest {
+	out := &PostmanRequest{
+		Method:      req.Method,
+		Description: req.Description,
+		Header:      []PostmanKV{},
+		URL: PostmanURL{
+			Host: []string{"{{baseUrl}}"},
+			Path: req.segments(func(name string) string { return ":" + name }),
+		},
+		Auth: postmanAuth(req.Auth),
+	}
+	if out.URL.Path == nil {
+		out.URL.Path = []string{}
+	}
+
+	for _, f := range req.PathParams {
+		out.URL.Variable = append(out.URL.Variable, PostmanKV{Key: f.Name, Value: f.Value, Description: f.Description})
+	}
+	for _, f := range req.Query {
+		out.URL.Query = append(out.URL.Query, PostmanKV{Key: f.Name, Value: f.Value, Description: f.Description, Disabled: !f.Required})
+	}
+	for _, f := range req.Headers {
+		out.Header = append(out.Header, PostmanKV{Key: f.Name, Value: f.Value, Description: f.Description, Disabled: !f.Required})
+	}
+	if len(req.Cookies) > 0 {
+		var cookies []string
+		for _, f := range req.Cookies {
+			cookies = append(cookies, f.Name+"="+f.Value)
+		}
+		out.Header = append(out.Header, PostmanKV{Key: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	if req.ContentType != "" {
+		out.Header = append(out.Header, PostmanKV{Key: "Content-Type", Value: req.ContentType})
+		out.Body = postmanBody(req)
+	}
+
+	out.URL.Raw = "{{baseUrl}}/" + strings.Join(out.URL.Path, "/")
+	var query []string
+	for _, kv := range out.URL.Query {
+		if !kv.Disabled {
+			query = append(query, kv.Key+"="+kv.Value)
+		}
+	}
+	if len(query) > 0 {
+		out.URL.Raw += "?" + strings.Join(query, "&")
+	}
+	return out
+}
+
+func postmanBody(req request) *PostmanBody {
+	if req.isForm() {
+		var kvs []PostmanKV
+		for _, f := range req.formFields() {
+			kv := PostmanKV{Key: f.Name, Value: f.Value}
+			if req.ContentType == "multipart/form-data" {
+				kv.Type = "text"
+				if f.File {
+					kv.Type = "file"
+				}
+			}
+			kvs = append(kvs, kv)
+		}
+		if req.ContentType == "multipart/form-data" {
+			return &PostmanBody{Mode: "formdata", FormData: kvs}
+		}
+		return &PostmanBody{Mode: "urlencoded", URLEncoded: kvs}
+	}
+
+	body := &PostmanBody{Mode: "raw", Raw: req.bodyText()}
+	if strings.Contains(req.ContentType, "json") {
+		body.Options = &PostmanBodyOptions{}
+		body.Options.Raw.Language = "json"
+	}
+	return body
+}
+
+// postmanAuth converts a request's credentials into Postman's, which refer
+// to the collection variables of the security scheme. Without a security
+// requirement the request inherits the collection's auth, which is none.
+func postmanAuth(a *auth) *PostmanAuth {
+	if a == nil {
+		return nil
+	}
+	str := func(key, value string) PostmanKV {
+		return PostmanKV{Key: key, Value: value, Type: "string"}
+	}
+	switch a.Type {
+	case "basic":
+		return &PostmanAuth{Type: "basic", Params: []PostmanKV{
+			str("username", "{{"+a.Scheme+"Username}}"),
+			str("password", "{{"+a.Scheme+"Password}}"),
+		}}
+	case "bearer":
+		return &PostmanAuth{Type: "bearer", Params: []PostmanKV{str("token", "{{"+a.Scheme+"}}")}}
+	case "apiKey":
+		return &PostmanAuth{Type: "apikey", Params: []PostmanKV{
+			str("key", a.Name),
+			str("value", "{{"+a.Scheme+"}}"),
+			str("in", a.In),
+		}}
+	case "oauth2":
+		return &PostmanAuth{Type: "oauth2", Params: []PostmanKV{
+			str("accessToken", "{{"+a.Scheme+"}}"),
+			str("addTokenTo", "header"),
+		}}
+	}
+	return &PostmanAuth{Type: "noauth"}
+}
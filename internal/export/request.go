@@ -0,0 +1,335 @@
+package export
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// request is the example request of an operation: every format is built
+// from it, so the exports of a spec agree on the values they send.
+type request struct {
+	operation
+	PathParams []field
+	Query      []field
+	Headers    []field
+	Cookies    []field
+
+	// ContentType is empty when the operation takes no body.
+	ContentType string
+	Body        interface{}
+	BodySchema  types.Schema
+
+	// Auth is nil when the operation states no security requirement.
+	Auth *auth
+}
+
+// field is a parameter or form field with its example value.
+type field struct {
+	Name        string
+	Value       string
+	Description string
+	Required    bool
+	File        bool
+}
+
+// auth is the credentials a request sends. Exports send one kind per
+// request, so it describes the first scheme of the operation's first
+// security requirement.
+type auth struct {
+	// Scheme names the security scheme; credential variables are named
+	// after it. It is empty when the requirement allows anonymous calls.
+	Scheme string
+	// Type is "none", "bearer", "basic", "apiKey" or "oauth2".
+	Type string
+	// Name and In locate an API key.
+	Name string
+	In   string
+}
+
+// exampleRequest builds the example request of an operation.
+func exampleRequest(spec *openapi.Spec, op operation) request {
+	req := request{operation: op, Auth: requestAuth(spec, op.Security)}
+
+	seen := map[string]bool{}
+	for _, param := range op.Parameters {
+		f := field{
+			Name:        param.Name,
+			Value:       paramExample(spec, op.key(), param),
+			Description: param.Description,
+			Required:    param.Required || param.In == "path",
+		}
+		switch param.In {
+		case "path":
+			seen[param.Name] = true
+			req.PathParams = append(req.PathParams, f)
+		case "query":
+			req.Query = append(req.Query, f)
+		case "header":
+			req.Headers = append(req.Headers, f)
+		case "cookie":
+			req.Cookies = append(req.Cookies, f)
+		}
+	}
+	// Parameters in the template that are not documented still need a value.
+	for _, name := range openapi.PathParams(op.Path) {
+		if !seen[name] {
+			param := types.Parameter{Name: name, In: "path", Schema: types.Schema{Type: "string"}}
+			req.PathParams = append(req.PathParams, field{Name: name, Value: paramExample(spec, op.key(), param), Required: true})
+		}
+	}
+
+	if op.RequestBody != nil {
+		if contentType, media, ok := requestMedia(op.RequestBody); ok {
+			req.ContentType = contentType
+			req.BodySchema = spec.ResolveSchema(media.Schema)
+			req.Body = bodyExample(spec, op.key(), media)
+		}
+	}
+	return req
+}
+
+// segments splits the path template, writing parameters with param, e.g.
+// as ":id" for Postman.
+func (r request) segments(param func(name string) string) []string {
+	var out []string
+	for _, segment := range strings.Split(strings.Trim(openapi.TemplatePath(r.Path), "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segment = param(strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		}
+		out = append(out, segment)
+	}
+	return out
+}
+
+// examplePath is the path with the example parameter values filled in.
+func (r request) examplePath() string {
+	values := map[string]string{}
+	for _, f := range r.PathParams {
+		values[f.Name] = f.Value
+	}
+	return "/" + strings.Join(r.segments(func(name string) string {
+		return url.PathEscape(values[name])
+	}), "/")
+}
+
+// wire is what an example request sends when it is replayed, as in a HAR
+// or a curl command: only the required optional parts, plus credentials as
+// {{variable}} placeholders named like the export variables.
+type wire struct {
+	Path    string
+	Query   []field
+	Headers []field
+	Cookies []field
+	// Basic holds the username and password placeholders of basic auth.
+	// The Authorization header carries them unencoded, as encoding the
+	// placeholders would hide them from whoever fills them in.
+	Basic *field
+}
+
+func (r request) wire() wire {
+	w := wire{Path: r.examplePath()}
+	for _, f := range r.Query {
+		if f.Required {
+			w.Query = append(w.Query, f)
+		}
+	}
+	for _, f := range r.Headers {
+		if f.Required {
+			w.Headers = append(w.Headers, f)
+		}
+	}
+	for _, f := range r.Cookies {
+		if f.Required {
+			w.Cookies = append(w.Cookies, f)
+		}
+	}
+
+	if a := r.Auth; a != nil {
+		placeholder := "{{" + a.Scheme + "}}"
+		switch a.Type {
+		case "basic":
+			w.Basic = &field{Name: "{{" + a.Scheme + "Username}}", Value: "{{" + a.Scheme + "Password}}"}
+			w.Headers = append(w.Headers, field{Name: "Authorization", Value: "Basic " + w.Basic.Name + ":" + w.Basic.Value})
+		case "bearer", "oauth2":
+			w.Headers = append(w.Headers, field{Name: "Authorization", Value: "Bearer " + placeholder})
+		case "apiKey":
+			credential := field{Name: a.Name, Value: placeholder}
+			switch a.In {
+			case "query":
+				w.Query = append(w.Query, credential)
+			case "cookie":
+				w.Cookies = append(w.Cookies, credential)
+			default:
+				w.Headers = append(w.Headers, credential)
+			}
+		}
+	}
+
+	if len(w.Cookies) > 0 {
+		cookies := make([]string, len(w.Cookies))
+		for i, c := range w.Cookies {
+			cookies[i] = c.Name + "=" + c.Value
+		}
+		w.Headers = append(w.Headers, field{Name: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+	if r.ContentType != "" {
+		w.Headers = append(w.Headers, field{Name: "Content-Type", Value: r.ContentType})
+	}
+	return w
+}
+
+// placeholders keeps {{variable}} placeholders readable in URLs.
+var placeholders = strings.NewReplacer("%7B%7B", "{{", "%7D%7D", "}}")
+
+// url is the full URL of the request against base.
+func (w wire) url(base string) string {
+	if len(w.Query) == 0 {
+		return base + w.Path
+	}
+	parts := make([]string, len(w.Query))
+	for i, f := range w.Query {
+		parts[i] = url.QueryEscape(f.Name) + "=" + placeholders.Replace(url.QueryEscape(f.Value))
+	}
+	return base + w.Path + "?" + strings.Join(parts, "&")
+}
+
+// isForm reports whether the body is sent as form fields.
+func (r request) isForm() bool {
+	return r.ContentType == "application/x-www-form-urlencoded" || r.ContentType == "multipart/form-data"
+}
+
+// formFields lists the fields of a form body by name.
+func (r request) formFields() []field {
+	values, _ := r.Body.(map[string]interface{})
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]field, 0, len(names))
+	for _, name := range names {
+		f := field{Name: name, Value: formatValue(values[name])}
+		if r.ContentType == "multipart/form-data" && r.BodySchema.Properties[name].Format == "binary" {
+			f.File, f.Value = true, ""
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// bodyText is the body as it is sent: indented JSON, an encoded form, or
+// text examples of other media types as they are.
+func (r request) bodyText() string {
+	if r.ContentType == "application/x-www-form-urlencoded" {
+		form := url.Values{}
+		for _, f := range r.formFields() {
+			form.Add(f.Name, f.Value)
+		}
+		return form.Encode()
+	}
+	if text, ok := r.Body.(string); ok && !strings.Contains(r.ContentType, "json") {
+		return text
+	}
+	data, _ := json.MarshalIndent(r.Body, "", "  ")
+	return string(data)
+}
+
+// requestMedia picks the media type a request body is sent as: JSON when
+// the operation accepts it, otherwise the first one by name.
+func requestMedia(body *types.RequestBody) (string, types.MediaTypeObject, bool) {
+	names := make([]string, 0, len(body.Content))
+	for name := range body.Content {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", types.MediaTypeObject{}, false
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "application/json" || strings.HasSuffix(name, "+json") {
+			return name, body.Content[name], true
+		}
+	}
+	return names[0], body.Content[names[0]], true
+}
+
+// requestAuth picks the credentials for an operation's security
+// requirements. Without requirements it returns nil, and the request
+// inherits whatever the export sets for the whole collection.
+func requestAuth(spec *openapi.Spec, security []types.SecurityRequirement) *auth {
+	if len(security) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(security[0]))
+	for name := range security[0] {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return &auth{Type: "none"}
+	}
+	sort.Strings(names)
+	a := schemeAuth(spec, names[0])
+	return &a
+}
+
+// schemeAuth describes the credentials of a security scheme. Unknown
+// schemes send none.
+func schemeAuth(spec *openapi.Spec, name string) auth {
+	var scheme types.SecurityScheme
+	if spec.Components != nil {
+		scheme = spec.Components.SecuritySchemes[name]
+	}
+	switch {
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		return auth{Scheme: name, Type: "basic"}
+	case scheme.Type == "http":
+		return auth{Scheme: name, Type: "bearer"}
+	case scheme.Type == "apiKey":
+		return auth{Scheme: name, Type: "apiKey", Name: scheme.Name, In: scheme.In}
+	case scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+		return auth{Scheme: name, Type: "oauth2"}
+	}
+	return auth{Type: "none"}
+}
+
+// variable is a credential the user fills in after importing an export.
+type variable struct {
+	Name        string
+	Secret      bool
+	Description string
+}
+
+// authVariables declares the variables the requests' credentials refer
+// to: one per security scheme, or a username and a password for basic
+// auth.
+func authVariables(spec *openapi.Spec) []variable {
+	if spec.Components == nil {
+		return nil
+	}
+	names := make([]string, 0, len(spec.Components.SecuritySchemes))
+	for name := range spec.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var vars []variable
+	for _, name := range names {
+		description := spec.Components.SecuritySchemes[name].Description
+		switch schemeAuth(spec, name).Type {
+		case "basic":
+			vars = append(vars,
+				variable{Name: name + "Username", Description: description},
+				variable{Name: name + "Password", Secret: true, Description: description})
+		case "bearer", "apiKey", "oauth2":
+			vars = append(vars, variable{Name: name, Secret: true, Description: description})
+		}
+	}
+	return vars
+}
@@ -0,0 +1,150 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// YAML writes v, e.g. a spec, as YAML. The document is the JSON encoding
+// of v with its key order kept, so it reads the same as the JSON.
+func YAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	switch {
+	case root.scalar():
+		b.WriteString(root.text() + "\n")
+	default:
+		writeBlock(&b, root, "")
+	}
+	return []byte(b.String()), nil
+}
+
+// node is a JSON value with the order of its object keys.
+type node struct {
+	// object and array are set for those kinds; value holds the rest.
+	object bool
+	array  bool
+	keys   []string
+	items  []*node
+	value  interface{}
+}
+
+func decodeNode(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		n := &node{object: true}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			item, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.keys = append(n.keys, key.(string))
+			n.items = append(n.items, item)
+		}
+		_, err := dec.Token()
+		return n, err
+	case json.Delim('['):
+		n := &node{array: true}
+		for dec.More() {
+			item, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		_, err := dec.Token()
+		return n, err
+	}
+	return &node{value: tok}, nil
+}
+
+// scalar reports whether the node is written on its key's line: plain
+// values, and empty objects and arrays.
+func (n *node) scalar() bool {
+	return (!n.object && !n.array) || len(n.items) == 0
+}
+
+func (n *node) text() string {
+	switch {
+	case n.object:
+		return "{}"
+	case n.array:
+		return "[]"
+	}
+	switch v := n.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	}
+	return ""
+}
+
+// writeBlock writes an object or array that has items, each line starting
+// with indent.
+func writeBlock(w io.StringWriter, n *node, indent string) {
+	for i, item := range n.items {
+		var lead string
+		if n.object {
+			lead = indent + yamlString(n.keys[i]) + ":"
+		} else {
+			lead = indent + "-"
+		}
+		switch {
+		case item.scalar():
+			w.WriteString(lead + " " + item.text() + "\n")
+		case n.array:
+			// An item of a sequence starts on the dash's line.
+			var b strings.Builder
+			writeBlock(&b, item, indent+"  ")
+			w.WriteString(lead + " " + strings.TrimPrefix(b.String(), indent+"  "))
+		default:
+			w.WriteString(lead + "\n")
+			writeBlock(w, item, indent+"  ")
+		}
+	}
+}
+
+// plainString matches the strings YAML reads back unchanged without
+// quotes, unless they are also yamlKeyword, which reads as something else.
+var (
+	plainString = regexp.MustCompile(`^[A-Za-z0-9_./$(][^:#\t\r\n]*$`)
+	yamlKeyword = regexp.MustCompile(`^(?i:y|n|yes|no|on|off|true|false|null|~|[-+]?\.?[0-9].*|\.inf|\.nan)$`)
+)
+
+// yamlString writes a string plainly when it can be, and otherwise as a
+// JSON string, which is valid YAML.
+func yamlString(s string) string {
+	if plainString.MatchString(s) && !yamlKeyword.MatchString(s) && strings.TrimSpace(s) == s {
+		return s
+	}
+	data, _ := json.Marshal(s)
+	return string(data)
+}
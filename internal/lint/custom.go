@@ -0,0 +1,141 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// CustomRule is a rule written in the lint config. It checks every value
+// of its target: the value must be set when Required is, and must match
+// Pattern when it is set.
+//
+//	{"id": "operation-id-verb", "target": "operationId", "required": true,
+//	 "pattern": "^(list|get|create|update|delete)[A-Z]",
+//	 "message": "operation IDs start with a verb"}
+type CustomRule struct {
+	ID string `json:"id"`
+	// Severity defaults to warning.
+	Severity    Severity `json:"severity,omitempty"`
+	Description string   `json:"description,omitempty"`
+	// Target is what the rule checks: "path", "operationId", "summary",
+	// "description", "tag", "parameter" (names), "property" (names) or
+	// "schema" (component names).
+	Target   string `json:"target"`
+	Pattern  string `json:"pattern,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Message replaces the default message of a finding.
+	Message string `json:"message,omitempty"`
+}
+
+var customTargets = map[string]bool{
+	"path": true, "operationId": true, "summary": true, "description": true,
+	"tag": true, "parameter": true, "property": true, "schema": true,
+}
+
+func (r CustomRule) validate() error {
+	switch {
+	case r.ID == "":
+		return fmt.Errorf("custom rule: id is required")
+	case !customTargets[r.Target]:
+		return fmt.Errorf("custom rule %q: unknown target %q", r.ID, r.Target)
+	case r.Severity != "" && !validSeverity(r.Severity):
+		return fmt.Errorf("custom rule %q: unknown severity %q", r.ID, r.Severity)
+	case r.Pattern == "" && !r.Required:
+		return fmt.Errorf("custom rule %q: set a pattern or required", r.ID)
+	}
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		return fmt.Errorf("custom rule %q: %w", r.ID, err)
+	}
+	return nil
+}
+
+// customRules turns the custom rules of the config into rules. The config
+// must be valid.
+func (c Config) customRules() []Rule {
+	rules := make([]Rule, 0, len(c.Custom))
+	for _, custom := range c.Custom {
+		severity := custom.Severity
+		if severity == "" {
+			severity = Warning
+		}
+		description := custom.Description
+		if description == "" {
+			description = custom.Message
+		}
+		rules = append(rules, Rule{
+			ID:          custom.ID,
+			Severity:    severity,
+			Description: description,
+			check:       custom.check,
+		})
+	}
+	return rules
+}
+
+func (r CustomRule) check(spec *openapi.Spec, _ Config, report reporter) {
+	pattern := regexp.MustCompile(r.Pattern)
+	// test reports value, named what, when it breaks the rule.
+	test := func(method, path, what, value string) {
+		var problem string
+		switch {
+		case strings.TrimSpace(value) == "":
+			if !r.Required {
+				return
+			}
+			problem = fmt.Sprintf("the %s is missing", what)
+		case r.Pattern != "" && !pattern.MatchString(value):
+			problem = fmt.Sprintf("%s %q does not match %s", what, value, r.Pattern)
+		default:
+			return
+		}
+		if r.Message != "" {
+			problem = r.Message + ": " + problem
+		}
+		report(method, path, "%s", problem)
+	}
+
+	switch r.Target {
+	case "path":
+		for _, path := range pathNames(spec.Paths) {
+			test("", path, "path", path)
+		}
+	case "schema":
+		if spec.Components != nil {
+			for _, name := range schemaNames(spec.Components.Schemas) {
+				test("", "", "schema name", name)
+			}
+		}
+	case "property":
+		eachSchema(spec, func(method, path, where string, schema types.Schema) {
+			eachProperty(schema, "", func(name, at string) {
+				test(method, path, "property name in the "+where, name)
+			})
+		})
+	default:
+		eachOperation(func(op operation, _ Config, _ reporter) {
+			switch r.Target {
+			case "operationId":
+				test(op.Method, op.Path, "operation ID", op.OperationID)
+			case "summary":
+				test(op.Method, op.Path, "summary", op.Summary)
+			case "description":
+				test(op.Method, op.Path, "description", op.Description)
+			case "tag":
+				if len(op.Tags) == 0 {
+					test(op.Method, op.Path, "tag", "")
+				}
+				for _, tag := range op.Tags {
+					test(op.Method, op.Path, "tag", tag)
+				}
+			case "parameter":
+				for _, param := range op.Parameters {
+					test(op.Method, op.Path, param.In+" parameter name", param.Name)
+				}
+			}
+		})(spec, Config{}, report)
+	}
+}
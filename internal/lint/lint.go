@@ -0,0 +1,218 @@
+// Package lint checks a spec against API conventions: the built-in rules,
+// and custom rules written in the lint config.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// Severity says how much a finding matters. Off turns a rule off.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+	Off     Severity = "off"
+)
+
+// Finding is one place where a spec breaks a rule. Method and Path are
+// empty for findings about the whole spec, such as its components.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Method   string   `json:"method,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Report is the result of a lint run.
+type Report struct {
+	Findings []Finding `json:"findings"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Infos    int       `json:"info"`
+}
+
+// Failed reports whether any finding is at least as severe as failOn,
+// which is Error or Warning.
+func (r *Report) Failed(failOn Severity) bool {
+	if failOn == Warning {
+		return r.Errors+r.Warnings > 0
+	}
+	return r.Errors > 0
+}
+
+// Text renders the report a finding per line, followed by the counts.
+func (r *Report) Text() string {
+	var b strings.Builder
+	for _, f := range r.Findings {
+		where := strings.TrimSpace(f.Method + " " + f.Path)
+		if where == "" {
+			where = "spec"
+		}
+		fmt.Fprintf(&b, "%-7s %s: %s [%s]\n", f.Severity, where, f.Message, f.Rule)
+	}
+	fmt.Fprintf(&b, "%d errors, %d warnings, %d info\n", r.Errors, r.Warnings, r.Infos)
+	return b.String()
+}
+
+// Config customises a lint run. The zero value runs the built-in rules at
+// their default severities.
+type Config struct {
+	// Rules sets the severity of rules by ID: "error", "warning", "info",
+	// or "off" to turn a rule off.
+	Rules map[string]Severity `json:"rules,omitempty"`
+
+	// Casing sets the casing the casing rules expect.
+	Casing Casing `json:"casing,omitempty"`
+
+	// Custom holds rules of the team's own.
+	Custom []CustomRule `json:"custom,omitempty"`
+}
+
+// Casing names the casing of path segments and property names: "kebab"
+// (user-groups), "snake" (user_groups), "camel" (userGroups) or "pascal"
+// (UserGroups). Paths default to kebab case and properties to camel case.
+type Casing struct {
+	Paths      string `json:"paths,omitempty"`
+	Properties string `json:"properties,omitempty"`
+}
+
+// LoadConfig reads a lint config from a JSON file.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read lint config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse lint config %s: %w", path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks that the config names known rules, severities and
+// casings, and that its custom rules are complete.
+func (c Config) Validate() error {
+	known := map[string]bool{}
+	for _, rule := range Rules {
+		known[rule.ID] = true
+	}
+	for _, rule := range c.Custom {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+		if known[rule.ID] {
+			return fmt.Errorf("custom rule %q: a rule with that ID exists", rule.ID)
+		}
+		known[rule.ID] = true
+	}
+	for id, severity := range c.Rules {
+		if !known[id] {
+			return fmt.Errorf("unknown lint rule %q", id)
+		}
+		if !validSeverity(severity) {
+			return fmt.Errorf("lint rule %q: unknown severity %q", id, severity)
+		}
+	}
+	for _, casing := range []string{c.Casing.Paths, c.Casing.Properties} {
+		if _, ok := casings[casing]; casing != "" && !ok {
+			return fmt.Errorf("unknown casing %q: use kebab, snake, camel or pascal", casing)
+		}
+	}
+	return nil
+}
+
+func validSeverity(s Severity) bool {
+	return s == Error || s == Warning || s == Info || s == Off
+}
+
+// Rule is a convention. Its check reports every place the spec breaks it.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	check       func(spec *openapi.Spec, cfg Config, report reporter)
+}
+
+type reporter func(method, path, format string, args ...interface{})
+
+// SeverityIn is the severity of the rule under the config.
+func (r Rule) SeverityIn(cfg Config) Severity {
+	if severity, ok := cfg.Rules[r.ID]; ok {
+		return severity
+	}
+	return r.Severity
+}
+
+// All lists the built-in rules followed by the custom rules of the config.
+func (c Config) All() []Rule {
+	return append(append([]Rule(nil), Rules...), c.customRules()...)
+}
+
+// Enabled lists the rules of the config, leaving out the rules turned off.
+func (c Config) Enabled() []Rule {
+	var rules []Rule
+	for _, rule := range c.All() {
+		if rule.SeverityIn(c) != Off {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Run checks a spec against the rules enabled in cfg. Findings are ordered
+// by path and method, with findings about the whole spec first.
+func Run(spec *openapi.Spec, cfg Config) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	report := &Report{Findings: []Finding{}}
+	for _, rule := range cfg.Enabled() {
+		rule := rule
+		severity := rule.SeverityIn(cfg)
+		seen := map[string]bool{}
+		rule.check(spec, cfg, func(method, path, format string, args ...interface{}) {
+			f := Finding{Rule: rule.ID, Severity: severity, Method: method, Path: path, Message: fmt.Sprintf(format, args...)}
+			// A schema shared by several operations is reported once.
+			key := f.Method + " " + f.Path + " " + f.Message
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+			report.Findings = append(report.Findings, f)
+			switch severity {
+			case Error:
+				report.Errors++
+			case Warning:
+				report.Warnings++
+			case Info:
+				report.Infos++
+			}
+		})
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return methodIndex(a.Method) < methodIndex(b.Method)
+	})
+	return report, nil
+}
+
+func methodIndex(method string) int {
+	for i, m := range openapi.Methods {
+		if m == method {
+			return i
+		}
+	}
+	return -1
+}
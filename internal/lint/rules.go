@@ -0,0 +1,322 @@
+package lint
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// Rules are the built-in rules.
+var Rules = []Rule{
+	{
+		ID:          "operation-summary",
+		Severity:    Warning,
+		Description: "Every operation has a summary.",
+		check: eachOperation(func(op operation, _ Config, report reporter) {
+			if strings.TrimSpace(op.Summary) == "" {
+				report(op.Method, op.Path, "the operation has no summary")
+			}
+		}),
+	},
+	{
+		ID:          "path-params-declared",
+		Severity:    Error,
+		Description: "Every parameter in a path template is declared as a path parameter.",
+		check: eachOperation(func(op operation, _ Config, report reporter) {
+			declared := map[string]bool{}
+			for _, param := range op.Parameters {
+				if param.In == "path" {
+					declared[param.Name] = true
+				}
+			}
+			for _, name := range openapi.PathParams(op.Path) {
+				if !declared[name] {
+					report(op.Method, op.Path, "path parameter %q is not declared", name)
+				}
+			}
+		}),
+	},
+	{
+		ID:          "error-responses",
+		Severity:    Warning,
+		Description: "Every operation documents at least one 4xx response.",
+		check: eachOperation(func(op operation, _ Config, report reporter) {
+			for status := range op.Responses {
+				if code, err := strconv.Atoi(status); (err == nil && code >= 400 && code < 500) || strings.EqualFold(status, "4XX") {
+					return
+				}
+			}
+			report(op.Method, op.Path, "the operation documents no 4xx response")
+		}),
+	},
+	{
+		ID:          "security-scheme-defined",
+		Severity:    Error,
+		Description: "Every security requirement of an operation names a security scheme of the spec.",
+		check: eachOperation(func(op operation, _ Config, report reporter) {
+			for _, requirement := range op.Security {
+				for _, name := range requirementNames(requirement) {
+					if op.spec.Components == nil || !hasScheme(op.spec.Components.SecuritySchemes, name) {
+						report(op.Method, op.Path, "security scheme %q is not defined", name)
+					}
+				}
+			}
+		}),
+	},
+	{
+		ID:          "unused-components",
+		Severity:    Warning,
+		Description: "Every component schema is referenced by an operation, directly or through other schemas.",
+		check: func(spec *openapi.Spec, _ Config, report reporter) {
+			if spec.Components == nil {
+				return
+			}
+			used := usedComponents(spec)
+			for _, name := range schemaNames(spec.Components.Schemas) {
+				if !used[name] {
+					report("", "", "component schema %q is not used", name)
+				}
+			}
+		},
+	},
+	{
+		ID:          "path-casing",
+		Severity:    Warning,
+		Description: "Path segments use the configured casing, kebab case by default.",
+		check: eachOperation(func(op operation, cfg Config, report reporter) {
+			casing := cfg.Casing.Paths
+			if casing == "" {
+				casing = "kebab"
+			}
+			for _, segment := range staticSegments(op.Path) {
+				if !casings[casing].MatchString(segment.Name) {
+					report(op.Method, op.Path, "path segment %q is not %s case", segment.Name, casing)
+				}
+			}
+		}),
+	},
+	{
+		ID:          "property-casing",
+		Severity:    Warning,
+		Description: "Schema properties use the configured casing, camel case by default.",
+		check: func(spec *openapi.Spec, cfg Config, report reporter) {
+			casing := cfg.Casing.Properties
+			if casing == "" {
+				casing = "camel"
+			}
+			eachSchema(spec, func(method, path, where string, schema types.Schema) {
+				eachProperty(schema, "", func(name, at string) {
+					if !casings[casing].MatchString(name) {
+						report(method, path, "property %q of the %s is not %s case", at, where, casing)
+					}
+				})
+			})
+		},
+	},
+	{
+		ID:          "plural-resources",
+		Severity:    Warning,
+		Description: "Collections are named in the plural: a segment followed by a parameter, such as users in /users/{id}.",
+		check: eachOperation(func(op operation, _ Config, report reporter) {
+			for _, segment := range staticSegments(op.Path) {
+				if segment.BeforeParam && !plural(segment.Name) {
+					report(op.Method, op.Path, "resource %q is not plural", segment.Name)
+				}
+			}
+		}),
+	},
+}
+
+// casings match a name in each casing.
+var casings = map[string]*regexp.Regexp{
+	"kebab":  regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`),
+	"snake":  regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`),
+	"camel":  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"pascal": regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+}
+
+// uncountable holds nouns that name collections without a plural "s".
+var uncountable = map[string]bool{
+	"children": true, "data": true, "feedback": true, "info": true,
+	"media": true, "metadata": true, "men": true, "news": true,
+	"people": true, "series": true, "staff": true, "women": true,
+}
+
+// plural guesses whether a resource name is plural from its last word.
+func plural(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	if len(words) == 0 {
+		return true
+	}
+	last := words[len(words)-1]
+	return uncountable[last] || (strings.HasSuffix(last, "s") && !strings.HasSuffix(last, "ss"))
+}
+
+// operation is an operation of the spec with where it lives.
+type operation struct {
+	Method string
+	Path   string
+	*openapi.Operation
+	spec *openapi.Spec
+}
+
+// eachOperation makes a check of single operations.
+func eachOperation(check func(op operation, cfg Config, report reporter)) func(*openapi.Spec, Config, reporter) {
+	return func(spec *openapi.Spec, cfg Config, report reporter) {
+		for _, path := range pathNames(spec.Paths) {
+			item := spec.Paths[path]
+			for _, method := range openapi.Methods {
+				if op := item.Operation(method); op != nil {
+					check(operation{Method: method, Path: path, Operation: op, spec: spec}, cfg, report)
+				}
+			}
+		}
+	}
+}
+
+// segment is a literal segment of a path template.
+type segment struct {
+	Name string
+	// BeforeParam is set when a parameter follows the segment.
+	BeforeParam bool
+}
+
+// staticSegments lists the literal segments of a path template.
+func staticSegments(path string) []segment {
+	var segments []segment
+	parts := strings.Split(openapi.TemplatePath(path), "/")
+	for i, part := range parts {
+		if part == "" || strings.HasPrefix(part, "{") {
+			continue
+		}
+		next := i+1 < len(parts) && strings.HasPrefix(parts[i+1], "{")
+		segments = append(segments, segment{Name: part, BeforeParam: next})
+	}
+	return segments
+}
+
+// eachSchema calls fn with every schema at the top of a request body,
+// response or component. where describes it, e.g. "request body".
+func eachSchema(spec *openapi.Spec, fn func(method, path, where string, schema types.Schema)) {
+	eachOperation(func(op operation, _ Config, _ reporter) {
+		if op.RequestBody != nil {
+			for _, media := range mediaNames(op.RequestBody.Content) {
+				fn(op.Method, op.Path, "request body", op.RequestBody.Content[media].Schema)
+			}
+		}
+		for _, status := range statusNames(op.Responses) {
+			for _, media := range mediaNames(op.Responses[status].Content) {
+				fn(op.Method, op.Path, status+" response", op.Responses[status].Content[media].Schema)
+			}
+		}
+	})(spec, Config{}, nil)
+	if spec.Components != nil {
+		for _, name := range schemaNames(spec.Components.Schemas) {
+			fn("", "", "schema "+name, spec.Components.Schemas[name])
+		}
+	}
+}
+
+// eachProperty calls fn with the properties of a schema at any depth, and
+// where they are, e.g. "address.city" or "items[].id".
+func eachProperty(schema types.Schema, prefix string, fn func(name, at string)) {
+	for _, name := range schemaNames(schema.Properties) {
+		prop := schema.Properties[name]
+		fn(name, prefix+name)
+		eachProperty(prop, prefix+name+".", fn)
+		if prop.Items != nil {
+			eachProperty(*prop.Items, prefix+name+"[].", fn)
+		}
+	}
+	if schema.Items != nil && prefix == "" {
+		eachProperty(*schema.Items, "[].", fn)
+	}
+}
+
+// usedComponents finds the component schemas the operations refer to,
+// directly or through other components.
+func usedComponents(spec *openapi.Spec) map[string]bool {
+	used := map[string]bool{}
+	var visit func(schema types.Schema)
+	visit = func(schema types.Schema) {
+		if name := strings.TrimPrefix(schema.Ref, "#/components/schemas/"); schema.Ref != "" && !used[name] {
+			used[name] = true
+			if target, ok := spec.Components.Schemas[name]; ok {
+				visit(target)
+			}
+		}
+		for _, prop := range schema.Properties {
+			visit(prop)
+		}
+		if schema.Items != nil {
+			visit(*schema.Items)
+		}
+	}
+	eachOperation(func(op operation, _ Config, _ reporter) {
+		for _, param := range op.Parameters {
+			visit(param.Schema)
+		}
+	})(spec, Config{}, nil)
+	eachSchema(spec, func(method, path, _ string, schema types.Schema) {
+		if path != "" {
+			visit(schema)
+		}
+	})
+	return used
+}
+
+func hasScheme(schemes map[string]types.SecurityScheme, name string) bool {
+	_, ok := schemes[name]
+	return ok
+}
+
+func pathNames(m map[string]openapi.PathItem) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func schemaNames(m map[string]types.Schema) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mediaNames(m map[string]types.MediaTypeObject) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func statusNames(m map[string]types.Response) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func requirementNames(m types.SecurityRequirement) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+// Package logging builds the server's structured logger.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config selects the log level and output format. CaptureDir, when set,
+// makes the server write every registration it receives to that directory
+// for debugging; payloads can contain source code, so it is off by default.
+type Config struct {
+	Level      string `json:"level,omitempty"`
+	Format     string `json:"format,omitempty"`
+	CaptureDir string `json:"captureDir,omitempty"`
+}
+
+// New returns a logger writing to w as configured. The level defaults to
+// info and the format to text.
+func New(cfg Config, w io.Writer) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want %q or %q)", cfg.Format, FormatText, FormatJSON)
+	}
+}
+
+// ParseLevel parses debug, info, warn or error; "" means info.
+func ParseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
@@ -0,0 +1,293 @@
+// Package metrics implements the few Prometheus metric types the server
+// exposes and renders them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry holds metrics in the order they were created.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) add(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText renders every metric in the text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// vec stores one value per combination of label values.
+type vec struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	values []string
+	value  float64
+
+	// Histograms only.
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newVec(name, help, kind string, labels []string) vec {
+	return vec{name: name, help: help, kind: kind, labels: labels, series: make(map[string]*series)}
+}
+
+// get returns the series for the label values, creating it if needed. The
+// caller must hold v.mu.
+func (v *vec) get(values []string) *series {
+	if len(values) != len(v.labels) {
+		panic("metrics: " + v.name + " expects " + strconv.Itoa(len(v.labels)) + " label values")
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		v.series[key] = s
+	}
+	return s
+}
+
+// sorted returns the series ordered by label values so output is stable.
+// The caller must hold v.mu.
+func (v *vec) sorted() []*series {
+	all := make([]*series, 0, len(v.series))
+	for _, s := range v.series {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].values, "\xff") < strings.Join(all[j].values, "\xff")
+	})
+	return all
+}
+
+func (v *vec) header(w *bufio.Writer) {
+	w.WriteString("# HELP " + v.name + " " + escapeHelp(v.help) + "\n")
+	w.WriteString("# TYPE " + v.name + " " + v.kind + "\n")
+}
+
+// Counter is a value that only goes up.
+type Counter struct{ vec }
+
+// NewCounter creates a counter with the given label names.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{newVec(name, help, "counter", labels)}
+	r.add(c)
+	return c
+}
+
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *Counter) Add(delta float64, values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.get(values).value += delta
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w)
+	for _, s := range c.sorted() {
+		writeSample(w, c.name, c.labels, s.values, "", "", s.value)
+	}
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct{ vec }
+
+// NewGauge creates a gauge with the given label names.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{newVec(name, help, "gauge", labels)}
+	r.add(g)
+	return g
+}
+
+func (g *Gauge) Set(value float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.get(values).value = value
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.header(w)
+	for _, s := range g.sorted() {
+		writeSample(w, g.name, g.labels, s.values, "", "", s.value)
+	}
+}
+
+// Sample is one value reported by a GaugeFunc.
+type Sample struct {
+	Values []string
+	Value  float64
+}
+
+// GaugeFunc is a gauge computed when the metrics are scraped.
+type GaugeFunc struct {
+	name    string
+	help    string
+	labels  []string
+	collect func() []Sample
+}
+
+// NewGaugeFunc creates a gauge whose samples come from collect at scrape
+// time. collect must return one label value per label name in each sample.
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func() []Sample) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, labels: labels, collect: collect}
+	r.add(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	w.WriteString("# HELP " + g.name + " " + escapeHelp(g.help) + "\n")
+	w.WriteString("# TYPE " + g.name + " gauge\n")
+	for _, s := range g.collect() {
+		writeSample(w, g.name, g.labels, s.Values, "", "", s.Value)
+	}
+}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	vec
+	buckets []float64
+}
+
+// NewHistogram creates a histogram with the given upper bucket bounds,
+// which must be sorted. The +Inf bucket is implied.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{vec: newVec(name, help, "histogram", labels), buckets: buckets}
+	r.add(h)
+	return h
+}
+
+func (h *Histogram) Observe(value float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.get(values)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(h.buckets))
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w)
+	for _, s := range h.sorted() {
+		for i, bound := range h.buckets {
+			writeSample(w, h.name+"_bucket", h.labels, s.values, "le", formatFloat(bound), float64(s.counts[i]))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, s.values, "le", "+Inf", float64(s.count))
+		writeSample(w, h.name+"_sum", h.labels, s.values, "", "", s.sum)
+		writeSample(w, h.name+"_count", h.labels, s.values, "", "", float64(s.count))
+	}
+}
+
+// ExponentialBuckets returns count bucket bounds starting at start, each
+// factor times the previous one.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// DefaultLatencyBuckets suit request and generation latencies in seconds.
+var DefaultLatencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + escapeLabel(values[i]) + `"`)
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraLabel + `="` + extraValue + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteText renders one metric of each type and compares the output
+// with testdata/metrics.txt.
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+
+	requests := r.NewCounter("test_requests_total", "Requests served.\nBy route.", "route", "status")
+	requests.Inc("/b", "200")
+	requests.Add(2, "/a", "200")
+	requests.Inc("/a", "404")
+
+	r.NewCounter("test_unused_total", "A counter without samples.")
+
+	temperature := r.NewGauge("test_temperature", "Current temperature.")
+	temperature.Set(-1.5)
+
+	r.NewGaugeFunc("test_apps", "Apps by name.", []string{"name"}, func() []Sample {
+		return []Sample{
+			{Values: []string{`quote " backslash \ newline` + "\n"}, Value: 1},
+			{Values: []string{"plain"}, Value: 2},
+		}
+	})
+
+	latency := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.5, "/a")
+	latency.Observe(5, "/a")
+
+	sizes := r.NewHistogram("test_size_bytes", "Sizes.", ExponentialBuckets(1024, 4, 3))
+	sizes.Observe(2048)
+
+	var out bytes.Buffer
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "metrics.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(want) {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0"},
+		{3, "3"},
+		{0.0005, "0.0005"},
+		{1e21, "1e+21"},
+	}
+	for _, tt := range tests {
+		if got := formatFloat(tt.value); got != tt.want {
+			t.Errorf("formatFloat(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLabelCountMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc() with too few label values did not panic")
+		}
+	}()
+	NewRegistry().NewCounter("test_total", "Test.", "a", "b").Inc("only-a")
+}
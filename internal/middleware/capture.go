@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/parser"
+)
+
+// registrationCapture is what debug capture writes for one registration.
+// Request headers are left out so tokens and signatures never hit the disk.
+type registrationCapture struct {
+	RequestID string                 `json:"requestId"`
+	Received  time.Time              `json:"received"`
+	RemoteIP  string                 `json:"remoteIp"`
+	App       int                    `json:"app"`
+	Analysis  []parser.RouteAnalysis `json:"analysis"`
+	Payload   json.RawMessage        `json:"payload"`
+}
+
+// capture writes the registration and how its routes were analysed to the
+// configured capture directory. Failures are logged and otherwise ignored.
+func (h *Handler) capture(ctx *fasthttp.RequestCtx, app int, analyses []parser.RouteAnalysis) {
+	logger := requestLogger(h.logger, ctx)
+	dir := h.config.Log.CaptureDir
+
+	now := time.Now().UTC()
+	data, err := json.MarshalIndent(registrationCapture{
+		RequestID: requestID(ctx),
+		Received:  now,
+		RemoteIP:  ctx.RemoteIP().String(),
+		App:       app,
+		Analysis:  analyses,
+		Payload:   json.RawMessage(ctx.PostBody()),
+	}, "", "  ")
+	if err != nil {
+		logger.Error("failed to encode registration capture", "error", err)
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		logger.Error("failed to create capture directory", "dir", dir, "error", err)
+		return
+	}
+	name := filepath.Join(dir, fmt.Sprintf("registration-%d-%s.json", app, now.Format("20060102T150405.000000000")))
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		logger.Error("failed to write registration capture", "file", name, "error", err)
+		return
+	}
+	logger.Debug("captured registration", "file", name)
+}
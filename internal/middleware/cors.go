@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/config"
+)
+
+// CORS wraps a handler with the configured cross-origin policy. Preflight
+// requests are answered here and never reach the wrapped handler.
+func CORS(cfg config.CORSConfig) func(fasthttp.RequestHandler) fasthttp.RequestHandler {
+	methods := strings.Join(cfg.AllowMethods, ", ")
+	headers := strings.Join(cfg.AllowHeaders, ", ")
+	expose := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := ""
+	if cfg.MaxAge > 0 {
+		maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			origin := string(ctx.Request.Header.Peek("Origin"))
+			preflight := ctx.IsOptions()
+
+			if origin != "" {
+				if allowed := allowOrigin(cfg, origin); allowed != "" {
+					h := &ctx.Response.Header
+					h.Set("Access-Control-Allow-Origin", allowed)
+					if allowed != "*" {
+						h.Add("Vary", "Origin")
+					}
+					if cfg.AllowCredentials {
+						h.Set("Access-Control-Allow-Credentials", "true")
+					}
+					if preflight {
+						h.Set("Access-Control-Allow-Methods", methods)
+						if headers != "" {
+							h.Set("Access-Control-Allow-Headers", headers)
+						} else if requested := ctx.Request.Header.Peek("Access-Control-Request-Headers"); len(requested) > 0 {
+							h.SetBytesV("Access-Control-Allow-Headers", requested)
+						}
+						if maxAge != "" {
+							h.Set("Access-Control-Max-Age", maxAge)
+						}
+					} else if expose != "" {
+						h.Set("Access-Control-Expose-Headers", expose)
+					}
+				}
+			}
+
+			if preflight {
+				ctx.SetStatusCode(fasthttp.StatusNoContent)
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or ""
+// when it is not allowed. A "*" entry allows any origin; with credentials
+// the origin is echoed since browsers reject the wildcard. Entries such as
+// "https://*.example.com" match any subdomain.
+func allowOrigin(cfg config.CORSConfig, origin string) string {
+	for _, allowed := range cfg.AllowOrigins {
+		switch {
+		case allowed == "*":
+			if cfg.AllowCredentials {
+				return origin
+			}
+			return "*"
+		case strings.EqualFold(allowed, origin):
+			return origin
+		case strings.Contains(allowed, "*."):
+			prefix, suffix, _ := strings.Cut(allowed, "*")
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) && len(origin) > len(prefix)+len(suffix) {
+				return origin
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/auth"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// GetDiagnostics reports where the app's spec breaks the OpenAPI rules, and
+// the registered routes left out of it.
+func (h *Handler) GetDiagnostics(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	viewer := viewerFrom(ctx)
+	writeJSON(ctx, fasthttp.StatusOK, map[string]interface{}{
+		"app":      port,
+		"problems": h.diagnose(viewer, port, h.registry.GetByPort(port), h.registry.GetMeta(port)),
+	})
+}
+
+// diagnose validates the spec the viewer sees, and the routes among theirs
+// that the spec leaves out. It never returns nil, so the JSON is a list.
+func (h *Handler) diagnose(viewer auth.Viewer, port string, routes []types.RouteInfo, meta types.AppMeta) []openapi.Problem {
+	problems := []openapi.Problem{}
+	problems = append(problems, openapi.Validate(h.viewSpec(viewer, port, routes, meta))...)
+	if !viewer.Authenticated && h.viewers.Filters(port) {
+		visible := make([]types.RouteInfo, 0, len(routes))
+		for _, route := range routes {
+			if !h.viewers.Hidden(port, viewer, route.Method, route.Path, route.Tags) {
+				visible = append(visible, route)
+			}
+		}
+		routes = visible
+	}
+	return append(problems, openapi.ValidateRoutes(routes)...)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/registry"
+)
+
+// eventPingInterval keeps idle streams open through proxies and lets the
+// server notice clients that went away.
+const eventPingInterval = 15 * time.Second
+
+// eventTicketTTL is how long the event stream ticket of a docs page stays
+// valid. A page keeps its stream open past it, but cannot reconnect.
+const eventTicketTTL = time.Hour
+
+// StreamEvents streams an app's registrations as Server-Sent Events. Each
+// new version is sent as a "registered" event whose ID is the version
+// number; a client reconnecting with an older Last-Event-ID is sent the
+// current version straight away.
+func (h *Handler) StreamEvents(ctx *fasthttp.RequestCtx) {
+	app := appPort(ctx)
+	lastID, _ := strconv.Atoi(string(ctx.Request.Header.Peek("Last-Event-ID")))
+	logger := requestLogger(h.logger, ctx)
+
+	ctx.SetContentType("text/event-stream")
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	ctx.Response.Header.Set("X-Accel-Buffering", "no")
+
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		events, cancel := h.registry.Subscribe(app)
+		defer cancel()
+		logger.Debug("event stream opened", "app", app)
+		defer logger.Debug("event stream closed", "app", app)
+
+		fmt.Fprint(w, "retry: 2000\n\n")
+		if history := h.registry.History(app); lastID > 0 && len(history) > 0 {
+			if current := history[len(history)-1]; current.Number != lastID {
+				writeEvent(w, registry.Event{App: app, Version: current})
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		ping := time.NewTicker(eventPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				writeEvent(w, event)
+			case <-ping.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func writeEvent(w *bufio.Writer, event registry.Event) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "event: registered\nid: %d\ndata: %s\n\n", event.Number, data)
+}
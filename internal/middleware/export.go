@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/codegen"
+	"github.com/yourusername/atomicdocs/internal/export"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// GetPostman serves the app's spec as a Postman collection.
+func (h *Handler) GetPostman(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, export.Postman(h.exportSpec(ctx)))
+}
+
+// GetInsomnia serves the app's spec as an Insomnia workspace.
+func (h *Handler) GetInsomnia(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, export.Insomnia(h.exportSpec(ctx)))
+}
+
+// GetHAR serves an example request for each of the app's operations as an
+// HTTP Archive.
+func (h *Handler) GetHAR(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, export.HARFile(h.exportSpec(ctx)))
+}
+
+// GetTypeScriptClient serves a fetch-based TypeScript client for the app.
+func (h *Handler) GetTypeScriptClient(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("application/typescript; charset=utf-8")
+	ctx.SetBody(codegen.TypeScript(h.exportSpec(ctx)))
+}
+
+// GetTypeScriptTypes serves the declarations of the app's TypeScript
+// client.
+func (h *Handler) GetTypeScriptTypes(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("application/typescript; charset=utf-8")
+	ctx.SetBody(codegen.TypeScriptTypes(h.exportSpec(ctx)))
+}
+
+func (h *Handler) exportSpec(ctx *fasthttp.RequestCtx) *openapi.Spec {
+	port := appPort(ctx)
+	return h.viewSpec(viewerFrom(ctx), port, h.registry.GetByPort(port), h.registry.GetMeta(port))
+}
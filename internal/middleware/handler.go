@@ -2,116 +2,193 @@ package middleware
 
 import (
 	"encoding/json"
-	"fmt"
-	"os"
+	"log/slog"
+	"strconv"
+	"time"
 	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/auth"
+	"github.com/yourusername/atomicdocs/internal/config"
 	"github.com/yourusername/atomicdocs/internal/openapi"
 	"github.com/yourusername/atomicdocs/internal/parser"
+	"github.com/yourusername/atomicdocs/internal/proxy"
 	"github.com/yourusername/atomicdocs/internal/registry"
+	"github.com/yourusername/atomicdocs/internal/router"
 	"github.com/yourusername/atomicdocs/internal/types"
 )
 
 type Handler struct {
 	registry *registry.Registry
+	config   *config.Config
+	verifier *auth.RegistrationVerifier
+	viewers  *auth.ViewerAuth
+	tickets  *auth.Tickets
+	metrics  *serverMetrics
+	logger   *slog.Logger
+	proxy    *proxy.Proxy
 }
 
 type RegistrationPayload struct {
 	Routes      []types.RouteInfo     `json:"routes"`
 	Port        int                   `json:"port"`
 	SchemaFiles map[string]string     `json:"schemaFiles"`
+	Info        *types.Info           `json:"info,omitempty"`
+	Servers     []types.Server        `json:"servers,omitempty"`
+	Tags        []types.Tag           `json:"tags,omitempty"`
+	GitSHA      string                `json:"gitSha,omitempty"`
 }
 
-func NewHandler(reg *registry.Registry) *Handler {
-	return &Handler{registry: reg}
+func NewHandler(reg *registry.Registry, cfg *config.Config, logger *slog.Logger) *Handler {
+	return &Handler{
+		registry: reg,
+		config:   cfg,
+		verifier: auth.NewRegistrationVerifier(cfg.Registration),
+		viewers:  auth.NewViewerAuth(cfg.Viewer),
+		tickets:  auth.NewTickets(eventTicketTTL),
+		metrics:  newServerMetrics(reg, time.Duration(cfg.Metrics.StaleAfterSeconds)*time.Second),
+		logger:   logger,
+		proxy:    proxy.New(cfg.Proxy),
+	}
 }
 
 func (h *Handler) RegisterRoutes(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Content-Type")
-	
-	if string(ctx.Method()) == "OPTIONS" {
-		ctx.SetStatusCode(fasthttp.StatusOK)
-		return
-	}
-	
+	logger := requestLogger(h.logger, ctx)
 	var payload RegistrationPayload
 	if err := json.Unmarshal(ctx.PostBody(), &payload); err != nil {
-		ctx.SetStatusCode(fasthttp.StatusBadRequest)
-		ctx.SetBodyString(`{"error":"invalid JSON"}`)
+		logger.Warn("rejected registration", "remote_ip", ctx.RemoteIP().String(), "error", err)
+		h.metrics.rejected(err)
+		writeError(ctx, fasthttp.StatusBadRequest, "invalid JSON")
 		return
 	}
 	
-	// Debug: write to file
-	f, _ := os.Create("/tmp/atomicdocs_debug.txt")
-	if f != nil {
-		f.WriteString(fmt.Sprintf("Routes: %d\n", len(payload.Routes)))
-		f.WriteString(fmt.Sprintf("Schema files: %d\n", len(payload.SchemaFiles)))
-		for path := range payload.SchemaFiles {
-			f.WriteString(fmt.Sprintf("  - %s\n", path))
-		}
-		if len(payload.Routes) > 0 {
-			f.WriteString(fmt.Sprintf("\nFirst route imports: %d\n", len(payload.Routes[0].Imports)))
-			for _, imp := range payload.Routes[0].Imports {
-				f.WriteString(fmt.Sprintf("  - %s from %s\n", imp.Name, imp.From))
-			}
-		}
-		f.Close()
+	creds := auth.Credentials{
+		Authorization: string(ctx.Request.Header.Peek("Authorization")),
+		Timestamp:     string(ctx.Request.Header.Peek(auth.TimestampHeader)),
+		Signature:     string(ctx.Request.Header.Peek(auth.SignatureHeader)),
+	}
+	if err := h.verifier.Verify(strconv.Itoa(payload.Port), creds, ctx.PostBody()); err != nil {
+		logger.Warn("rejected registration", "app", payload.Port, "remote_ip", ctx.RemoteIP().String(), "error", err)
+		h.metrics.rejected(err)
+		writeError(ctx, fasthttp.StatusUnauthorized, err.Error())
+		return
 	}
 	
 	analyzedRoutes := make([]types.RouteInfo, len(payload.Routes))
+	analyses := make([]parser.RouteAnalysis, len(payload.Routes))
 	for i, route := range payload.Routes {
-		analyzedRoutes[i] = parser.AnalyzeRoute(route, payload.SchemaFiles)
+		analyzedRoutes[i], analyses[i] = parser.AnalyzeRoute(route, payload.SchemaFiles)
+	}
+	
+	meta := types.AppMeta{
+		Info:    payload.Info,
+		Servers: payload.Servers,
+		Tags:    payload.Tags,
 	}
 	
-	h.registry.RegisterApp(payload.Port, analyzedRoutes)
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	ctx.SetBodyString(`{"status":"registered"}`)
+	version := h.registry.RegisterApp(payload.Port, analyzedRoutes, meta, payload.GitSHA)
+	h.metrics.registered(strconv.Itoa(payload.Port), len(ctx.PostBody()), analyses)
+	summary := parser.Summarize(analyses)
+	logger.Info("registered app",
+		"app", payload.Port,
+		"version", version.Number,
+		"routes", version.RouteCount,
+		"schema_files", len(payload.SchemaFiles),
+		"schemas_parsed", summary.Parsed,
+		"route_warnings", summary.Warnings)
+	if h.config.Log.CaptureDir != "" {
+		h.capture(ctx, payload.Port, analyses)
+	}
+
+	// The spec is served whatever its problems; the app is told about them.
+	warnings := h.diagnose(auth.Viewer{Authenticated: true}, strconv.Itoa(payload.Port), analyzedRoutes, meta)
+	if len(warnings) > 0 {
+		logger.Warn("registered spec is not valid OpenAPI", "app", payload.Port, "problems", len(warnings))
+	}
+	writeJSON(ctx, fasthttp.StatusOK, map[string]interface{}{
+		"status":   "registered",
+		"version":  version.Number,
+		"hash":     version.Hash,
+		"summary":  summary,
+		"routes":   analyses,
+		"warnings": warnings,
+	})
 }
 
 func (h *Handler) GetSpec(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.Set("Content-Type", "application/json")
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	
-	portHeader := string(ctx.Request.Header.Peek("X-App-Port"))
-	if portHeader == "" {
-		portHeader = "3000"
+	port := appPort(ctx)
+	spec := h.viewSpec(viewerFrom(ctx), port, h.registry.GetByPort(port), h.registry.GetMeta(port))
+	writeJSON(ctx, fasthttp.StatusOK, spec)
+}
+
+// ListApps lists the registered apps visible to the viewer with their
+// current versions.
+func (h *Handler) ListApps(ctx *fasthttp.RequestCtx) {
+	viewer := h.identify(ctx)
+	apps := []registry.AppSummary{}
+	for _, app := range h.registry.Apps() {
+		if h.viewers.CanView(app.App, viewer) {
+			apps = append(apps, app)
+		}
 	}
-	
-	routes := h.registry.GetByPort(portHeader)
-	spec := openapi.Generate(routes, "http://localhost:"+portHeader)
-	
-	data, _ := json.Marshal(spec)
-	ctx.SetBody(data)
+	writeJSON(ctx, fasthttp.StatusOK, map[string]interface{}{"apps": apps})
+}
+
+// GetApp describes one registered app.
+func (h *Handler) GetApp(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	history := h.registry.History(port)
+	if len(history) == 0 {
+		writeError(ctx, fasthttp.StatusNotFound, "app not registered")
+		return
+	}
+	writeJSON(ctx, fasthttp.StatusOK, map[string]interface{}{
+		"app":     port,
+		"current": history[len(history)-1],
+	})
 }
 
-func (h *Handler) ServeUI(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.Set("Content-Type", "text/html")
-	ctx.SetBodyString(swaggerHTML)
+func (h *Handler) buildSpec(port string, routes []types.RouteInfo, meta types.AppMeta) *openapi.Spec {
+	start := time.Now()
+	spec := openapi.Generate(routes, "http://localhost:"+port, openapi.MergeMeta(h.config.Docs, meta))
+	// Any port can be asked for; only registered apps get a series.
+	if h.registry.Registered(port) {
+		h.metrics.specDuration.Observe(time.Since(start).Seconds(), port)
+	}
+	return spec
 }
 
-const swaggerHTML = `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <title>API Documentation</title>
-    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.10.5/swagger-ui.css">
-</head>
-<body>
-    <div id="swagger-ui"></div>
-    <script src="https://unpkg.com/swagger-ui-dist@5.10.5/swagger-ui-bundle.js"></script>
-    <script src="https://unpkg.com/swagger-ui-dist@5.10.5/swagger-ui-standalone-preset.js"></script>
-    <script>
-        window.onload = () => {
-            SwaggerUIBundle({
-                url: '/docs/json',
-                dom_id: '#swagger-ui',
-                presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
-                layout: "BaseLayout",
-                deepLinking: true,
-                tryItOutEnabled: true
-            });
-        };
-    </script>
-</body>
-</html>`
+// appPort identifies the app a request is about: the {app} path parameter,
+// the "app" or "port" query argument, then the X-App-Port header set by the
+// framework proxies, then 3000.
+func appPort(ctx *fasthttp.RequestCtx) string {
+	if port := router.Param(ctx, "app"); port != "" {
+		return port
+	}
+	if port := string(ctx.QueryArgs().Peek("app")); port != "" {
+		return port
+	}
+	if port := string(ctx.QueryArgs().Peek("port")); port != "" {
+		return port
+	}
+	if port := string(ctx.Request.Header.Peek("X-App-Port")); port != "" {
+		return port
+	}
+	return "3000"
+}
+
+func writeJSON(ctx *fasthttp.RequestCtx, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(ctx, fasthttp.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	ctx.SetStatusCode(status)
+	ctx.SetContentType("application/json")
+	ctx.SetBody(data)
+}
+
+func writeError(ctx *fasthttp.RequestCtx, status int, message string) {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	ctx.SetStatusCode(status)
+	ctx.SetContentType("application/json")
+	ctx.SetBody(data)
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// BuildInfo identifies the running binary. Release builds set the fields
+// through -ldflags; development builds fall back to the VCS stamp Go embeds.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Complete fills in whatever -ldflags left empty from the embedded build
+// information.
+func (b BuildInfo) Complete() BuildInfo {
+	b.GoVersion = runtime.Version()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if b.Commit == "" {
+					b.Commit = setting.Value
+				}
+			case "vcs.time":
+				if b.BuildDate == "" {
+					b.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+	if b.Version == "" {
+		b.Version = "dev"
+	}
+	return b
+}
+
+// Healthz reports that the process is up and serving requests.
+func (h *Handler) Healthz(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyTimeout bounds how long Readyz waits for the registry.
+const readyTimeout = time.Second
+
+// Readyz reports whether the server can accept registrations: the registry
+// must respond and, when registrations are captured, the capture directory
+// must be writable.
+func (h *Handler) Readyz(ctx *fasthttp.RequestCtx) {
+	if err := h.ready(); err != nil {
+		writeJSON(ctx, fasthttp.StatusServiceUnavailable, map[string]string{
+			"status": "unavailable",
+			"error":  err.Error(),
+		})
+		return
+	}
+	writeJSON(ctx, fasthttp.StatusOK, map[string]string{"status": "ready"})
+}
+
+func (h *Handler) ready() error {
+	if err := h.registry.Ping(readyTimeout); err != nil {
+		return err
+	}
+	if dir := h.config.Log.CaptureDir; dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("capture directory: %w", err)
+		}
+		f, err := os.CreateTemp(dir, ".readyz-*")
+		if err != nil {
+			return fmt.Errorf("capture directory: %w", err)
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return nil
+}
+
+// Version reports the build information of the server.
+func Version(info BuildInfo) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		writeJSON(ctx, fasthttp.StatusOK, info)
+	}
+}
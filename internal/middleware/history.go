@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/diff"
+	"github.com/yourusername/atomicdocs/internal/registry"
+	"github.com/yourusername/atomicdocs/internal/router"
+)
+
+// GetDiff compares an app's current registration with the one it replaced.
+func (h *Handler) GetDiff(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	viewer := viewerFrom(ctx)
+
+	prevRoutes, prevMeta, ok := h.registry.GetPrevious(port)
+	if !ok {
+		writeError(ctx, fasthttp.StatusNotFound, "no previous registration")
+		return
+	}
+
+	old := h.viewSpec(viewer, port, prevRoutes, prevMeta)
+	current := h.viewSpec(viewer, port, h.registry.GetByPort(port), h.registry.GetMeta(port))
+	report := diff.Compare(old, current)
+
+	if string(ctx.QueryArgs().Peek("format")) == "markdown" {
+		ctx.SetContentType("text/markdown; charset=utf-8")
+		ctx.SetBodyString(report.Markdown())
+		return
+	}
+
+	writeJSON(ctx, fasthttp.StatusOK, report)
+}
+
+// GetHistory lists the stored versions of an app.
+func (h *Handler) GetHistory(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	writeJSON(ctx, fasthttp.StatusOK, map[string]interface{}{
+		"app":      port,
+		"versions": h.registry.History(port),
+	})
+}
+
+// GetVersionSpec returns the spec as it was at a stored version.
+func (h *Handler) GetVersionSpec(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+
+	raw := router.Param(ctx, "version")
+	if raw == "" {
+		raw = string(ctx.QueryArgs().Peek("version"))
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil {
+		writeError(ctx, fasthttp.StatusBadRequest, "version must be a number")
+		return
+	}
+
+	snap, ok := h.registry.GetVersion(port, version)
+	if !ok {
+		writeError(ctx, fasthttp.StatusNotFound, "version not found")
+		return
+	}
+
+	writeJSON(ctx, fasthttp.StatusOK, h.viewSpec(viewerFrom(ctx), port, snap.Routes, snap.Meta))
+}
+
+// GetChangelog renders the changes between two stored versions of an app.
+// Without "from" and "to" it describes the latest version against the one
+// before it.
+func (h *Handler) GetChangelog(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	viewer := viewerFrom(ctx)
+
+	history := h.registry.History(port)
+	if len(history) == 0 {
+		writeError(ctx, fasthttp.StatusNotFound, "app not registered")
+		return
+	}
+
+	to := history[len(history)-1].Number
+	from := to - 1
+	if v := ctx.QueryArgs().Peek("to"); len(v) > 0 {
+		n, err := strconv.Atoi(string(v))
+		if err != nil {
+			writeError(ctx, fasthttp.StatusBadRequest, "to must be a number")
+			return
+		}
+		to, from = n, n-1
+	}
+	explicitFrom := false
+	if v := ctx.QueryArgs().Peek("from"); len(v) > 0 {
+		n, err := strconv.Atoi(string(v))
+		if err != nil {
+			writeError(ctx, fasthttp.StatusBadRequest, "from must be a number")
+			return
+		}
+		from, explicitFrom = n, true
+	}
+
+	newer, ok := h.registry.GetVersion(port, to)
+	if !ok {
+		writeError(ctx, fasthttp.StatusNotFound, "version not found")
+		return
+	}
+	older, ok := h.registry.GetVersion(port, from)
+	if !ok && explicitFrom {
+		writeError(ctx, fasthttp.StatusNotFound, "version not found")
+		return
+	}
+	if !ok {
+		// The first version, or one that fell out of the history window, is
+		// compared against an empty app so every endpoint shows up as added.
+		older = registry.Snapshot{}
+	}
+
+	report := diff.Compare(h.viewSpec(viewer, port, older.Routes, older.Meta), h.viewSpec(viewer, port, newer.Routes, newer.Meta))
+
+	if string(ctx.QueryArgs().Peek("format")) == "json" {
+		writeJSON(ctx, fasthttp.StatusOK, report)
+		return
+	}
+
+	heading := fmt.Sprintf("Version %d", newer.Number)
+	details := []string{newer.Timestamp.Format("2006-01-02 15:04 MST")}
+	if newer.GitSHA != "" {
+		details = append(details, "commit "+newer.GitSHA)
+	}
+	subheading := fmt.Sprintf("%s. Compared with version %d.", strings.Join(details, ", "), from)
+	if older.Number == 0 {
+		subheading = strings.Join(details, ", ") + ". First recorded version."
+	}
+
+	ctx.SetContentType("text/markdown; charset=utf-8")
+	ctx.SetBodyString(report.Changelog(heading, subheading))
+}
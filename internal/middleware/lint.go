@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/lint"
+)
+
+// GetLint checks the app's spec against the lint rules of the config and
+// serves the report.
+func (h *Handler) GetLint(ctx *fasthttp.RequestCtx) {
+	report, err := lint.Run(h.exportSpec(ctx), h.config.Lint)
+	if err != nil {
+		writeError(ctx, fasthttp.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(ctx, fasthttp.StatusOK, report)
+}
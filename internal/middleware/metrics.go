@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/auth"
+	"github.com/yourusername/atomicdocs/internal/metrics"
+	"github.com/yourusername/atomicdocs/internal/parser"
+	"github.com/yourusername/atomicdocs/internal/registry"
+	"github.com/yourusername/atomicdocs/internal/router"
+)
+
+// serverMetrics holds the metrics served on /metrics.
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	requests          *metrics.Counter
+	requestDuration   *metrics.Histogram
+	registrations     *metrics.Counter
+	rejections        *metrics.Counter
+	payloadBytes      *metrics.Histogram
+	specDuration      *metrics.Histogram
+	parseFailures     *metrics.Counter
+	lastRegistrations *metrics.Gauge
+	proxyDuration     *metrics.Histogram
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+	latest   time.Time
+}
+
+func newServerMetrics(reg *registry.Registry, staleAfter time.Duration) *serverMetrics {
+	r := metrics.NewRegistry()
+	m := &serverMetrics{
+		registry: r,
+		lastSeen: make(map[string]time.Time),
+
+		requests: r.NewCounter("atomicdocs_http_requests_total",
+			"HTTP requests served, by route pattern, method and status.", "route", "method", "status"),
+		requestDuration: r.NewHistogram("atomicdocs_http_request_duration_seconds",
+			"Time spent serving HTTP requests, by route pattern and method.", metrics.DefaultLatencyBuckets, "route", "method"),
+		registrations: r.NewCounter("atomicdocs_registrations_total",
+			"Accepted route registrations, by app.", "app"),
+		rejections: r.NewCounter("atomicdocs_registration_rejections_total",
+			"Rejected route registrations, by reason.", "reason"),
+		payloadBytes: r.NewHistogram("atomicdocs_registration_payload_bytes",
+			"Size of accepted registration payloads, by app.", metrics.ExponentialBuckets(1024, 4, 8), "app"),
+		specDuration: r.NewHistogram("atomicdocs_spec_generation_seconds",
+			"Time spent generating OpenAPI documents, by app.", metrics.DefaultLatencyBuckets, "app"),
+		parseFailures: r.NewCounter("atomicdocs_schema_parse_failures_total",
+			"Imported schemas that were found but could not be parsed, by schema library.", "library"),
+		lastRegistrations: r.NewGauge("atomicdocs_app_last_registration_timestamp_seconds",
+			"Unix time of the last accepted registration, by app.", "app"),
+		proxyDuration: r.NewHistogram("atomicdocs_proxy_upstream_duration_seconds",
+			"Time apps took to answer try-it requests, by app and status; status is \"error\" when the app could not be reached.", metrics.DefaultLatencyBuckets, "app", "status"),
+	}
+
+	r.NewGaugeFunc("atomicdocs_app_routes",
+		"Routes in the current version of each app.", []string{"app"},
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for _, app := range reg.Apps() {
+				samples = append(samples, metrics.Sample{Values: []string{app.App}, Value: float64(app.Current.RouteCount)})
+			}
+			return samples
+		})
+	r.NewGaugeFunc("atomicdocs_apps",
+		"Registered apps.", nil,
+		func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(len(reg.Apps()))}}
+		})
+	r.NewGaugeFunc("atomicdocs_stale_apps",
+		"Apps that have not registered within the configured stale window.", nil,
+		func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(m.staleApps(staleAfter))}}
+		})
+	r.NewGaugeFunc("atomicdocs_last_registration_timestamp_seconds",
+		"Unix time of the last accepted registration from any app, 0 before the first one.", nil,
+		func() []metrics.Sample {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			var value float64
+			if !m.latest.IsZero() {
+				value = float64(m.latest.Unix())
+			}
+			return []metrics.Sample{{Value: value}}
+		})
+
+	return m
+}
+
+// registered records an accepted registration. Re-registrations of an
+// unchanged app count too: they show the app is still alive.
+func (m *serverMetrics) registered(app string, size int, analyses []parser.RouteAnalysis) {
+	now := time.Now()
+	m.registrations.Inc(app)
+	m.payloadBytes.Observe(float64(size), app)
+	m.lastRegistrations.Set(float64(now.Unix()), app)
+
+	for _, analysis := range analyses {
+		for _, imp := range analysis.Imports {
+			if imp.File != "" && !imp.Parsed {
+				m.parseFailures.Inc(imp.Library)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.lastSeen[app] = now
+	m.latest = now
+	m.mu.Unlock()
+}
+
+func (m *serverMetrics) rejected(err error) {
+	m.rejections.Inc(rejectionReason(err))
+}
+
+func (m *serverMetrics) staleApps(staleAfter time.Duration) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stale := 0
+	for _, seen := range m.lastSeen {
+		if time.Since(seen) > staleAfter {
+			stale++
+		}
+	}
+	return stale
+}
+
+func rejectionReason(err error) string {
+	switch {
+	case errors.Is(err, auth.ErrMissingCredentials):
+		return "missing_credentials"
+	case errors.Is(err, auth.ErrInvalidToken):
+		return "invalid_token"
+	case errors.Is(err, auth.ErrInvalidSignature):
+		return "invalid_signature"
+	case errors.Is(err, auth.ErrStaleTimestamp):
+		return "stale_timestamp"
+	case errors.Is(err, auth.ErrReplayed):
+		return "replayed"
+	default:
+		return "invalid_payload"
+	}
+}
+
+// Instrument counts and times every request by the pattern of the route
+// that served it. Requests no route matched are grouped as "unmatched" so
+// scanners cannot blow up the label space.
+func (h *Handler) Instrument(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+		next(ctx)
+
+		route := router.Pattern(ctx)
+		if route == "" {
+			route = "unmatched"
+		}
+		method := string(ctx.Method())
+		switch method {
+		case fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodPost, fasthttp.MethodPut,
+			fasthttp.MethodPatch, fasthttp.MethodDelete, fasthttp.MethodOptions:
+		default:
+			method = "OTHER"
+		}
+		h.metrics.requests.Inc(route, method, strconv.Itoa(ctx.Response.StatusCode()))
+		h.metrics.requestDuration.Observe(time.Since(start).Seconds(), route, method)
+	}
+}
+
+// ServeMetrics serves the metrics in the Prometheus text format. Series are
+// labelled by app, so when any app is private only authenticated viewers
+// may scrape them.
+func (h *Handler) ServeMetrics(ctx *fasthttp.RequestCtx) {
+	if h.viewers.Private() && !h.identify(ctx).Authenticated {
+		ctx.Response.Header.Set("WWW-Authenticate", h.viewers.Challenge())
+		writeError(ctx, fasthttp.StatusUnauthorized, "authentication required")
+		return
+	}
+	ctx.SetContentType(metrics.ContentType)
+	if err := h.metrics.registry.WriteText(ctx); err != nil {
+		writeError(ctx, fasthttp.StatusInternalServerError, "failed to write metrics")
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/mock"
+	"github.com/yourusername/atomicdocs/internal/router"
+	"github.com/yourusername/atomicdocs/validate"
+)
+
+// mockProblem is the RFC 7807 body of requests the mock rejects for not
+// matching the documented API.
+type mockProblem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Errors   []validate.FieldError `json:"errors,omitempty"`
+}
+
+// ServeMock answers /mock/{app}/{path...} as the app would according to its
+// docs: the request is matched to a documented operation and answered with
+// the documented example or data synthesised from the response schema.
+// X-Mock-Status selects a documented status, X-Mock-Seed varies the data,
+// and X-Mock-Validate checks the request first.
+func (h *Handler) ServeMock(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	routes := h.registry.GetByPort(port)
+	if len(routes) == 0 {
+		writeError(ctx, fasthttp.StatusNotFound, "app not registered")
+		return
+	}
+	spec := h.viewSpec(viewerFrom(ctx), port, routes, h.registry.GetMeta(port))
+
+	path := "/" + router.Param(ctx, "path")
+	match, allowed := mock.Find(spec, string(ctx.Method()), path)
+	if match == nil {
+		if len(allowed) > 0 {
+			ctx.Response.Header.Set("Allow", strings.Join(allowed, ", "))
+			writeError(ctx, fasthttp.StatusMethodNotAllowed, "method not documented for "+path)
+			return
+		}
+		writeError(ctx, fasthttp.StatusNotFound, "no documented operation matches "+path)
+		return
+	}
+
+	seed := h.config.Mock.Seed
+	if raw := ctx.Request.Header.Peek(mock.SeedHeader); len(raw) > 0 {
+		parsed, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			writeError(ctx, fasthttp.StatusBadRequest, "invalid "+mock.SeedHeader+" header")
+			return
+		}
+		seed = parsed
+	}
+
+	shouldValidate := h.config.Mock.Validate
+	if raw := ctx.Request.Header.Peek(mock.ValidateHeader); len(raw) > 0 {
+		parsed, err := strconv.ParseBool(string(raw))
+		if err != nil {
+			writeError(ctx, fasthttp.StatusBadRequest, "invalid "+mock.ValidateHeader+" header")
+			return
+		}
+		shouldValidate = parsed
+	}
+	if shouldValidate {
+		status, errs := validate.CheckRequest(match.Operation.Parameters, match.Operation.RequestBody, validate.Request{
+			PathParams: match.Params,
+			Query: func(name string) []string {
+				var values []string
+				for _, value := range ctx.QueryArgs().PeekMulti(name) {
+					values = append(values, string(value))
+				}
+				return values
+			},
+			Header: func(name string) string {
+				return string(ctx.Request.Header.Peek(name))
+			},
+			ContentType: string(ctx.Request.Header.ContentType()),
+			Body:        ctx.PostBody(),
+		}, spec.ResolveSchema)
+		if len(errs) > 0 {
+			data, _ := json.Marshal(mockProblem{
+				Type:     "about:blank",
+				Title:    fasthttp.StatusMessage(status),
+				Status:   status,
+				Detail:   "The request does not match the documented API.",
+				Instance: string(ctx.RequestURI()),
+				Errors:   errs,
+			})
+			ctx.SetStatusCode(status)
+			ctx.SetContentType("application/problem+json")
+			ctx.SetBody(data)
+			return
+		}
+	}
+
+	resp, err := mock.Respond(spec, match, string(ctx.Request.Header.Peek(mock.StatusHeader)), seed, path)
+	if err != nil {
+		writeError(ctx, fasthttp.StatusBadRequest, err.Error())
+		return
+	}
+	for name, value := range resp.Headers {
+		ctx.Response.Header.Set(name, value)
+	}
+	ctx.SetStatusCode(resp.Status)
+	if resp.ContentType != "" {
+		ctx.SetContentType(resp.ContentType)
+	}
+	ctx.SetBody(resp.Body)
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/proxy"
+	"github.com/yourusername/atomicdocs/internal/router"
+)
+
+// ProxyRequest forwards a try-it request for /api/v1/proxy/{app}/{path...}
+// to the app, at the server named by the X-AtomicDocs-Target header or the
+// app's first server. Servers the app did not register are refused, so the
+// proxy cannot be pointed at arbitrary hosts; while registration is open,
+// so are servers outside the configured allow list. When registration
+// requires credentials, the app is also reached on its own port on this
+// machine, which private addresses are otherwise refused for.
+func (h *Handler) ProxyRequest(ctx *fasthttp.RequestCtx) {
+	port := appPort(ctx)
+	routes := h.registry.GetByPort(port)
+	if len(routes) == 0 {
+		writeError(ctx, fasthttp.StatusNotFound, "app not registered")
+		return
+	}
+	spec := h.viewSpec(viewerFrom(ctx), port, routes, h.registry.GetMeta(port))
+
+	servers := openapi.ServerURLs(spec.Servers, "http://localhost:"+port)
+	trusted := h.config.Registration.Enabled()
+	target, err := proxy.Target(servers, string(ctx.Request.Header.Peek(proxy.TargetHeader)), "/"+router.Param(ctx, "path"))
+	if err == nil {
+		err = h.proxy.Check(target, trusted)
+	}
+	if err != nil {
+		writeError(ctx, fasthttp.StatusForbidden, err.Error())
+		return
+	}
+
+	elapsed, err := h.proxy.Forward(ctx, target, documentedHeaders(spec), trusted && proxy.AppTarget(target, port))
+	status := strconv.Itoa(ctx.Response.StatusCode())
+	switch {
+	case err == nil:
+	case errors.Is(err, proxy.ErrRequestTooLarge):
+		writeError(ctx, fasthttp.StatusRequestEntityTooLarge, err.Error())
+		return
+	case errors.Is(err, proxy.ErrPrivateAddress):
+		writeError(ctx, fasthttp.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, fasthttp.ErrTimeout):
+		status = "error"
+		writeError(ctx, fasthttp.StatusGatewayTimeout, "the app did not answer in time")
+	case errors.Is(err, fasthttp.ErrBodyTooLarge):
+		status = "error"
+		writeError(ctx, fasthttp.StatusBadGateway, "the app's response is too large")
+	default:
+		status = "error"
+		writeError(ctx, fasthttp.StatusBadGateway, "failed to reach the app: "+err.Error())
+	}
+
+	h.metrics.proxyDuration.Observe(elapsed.Seconds(), port, status)
+	requestLogger(h.logger, ctx).Debug("proxied request",
+		"app", port,
+		"method", string(ctx.Method()),
+		"target", target,
+		"status", status,
+		"duration", elapsed)
+}
+
+// documentedHeaders lists the request headers the spec documents, as
+// parameters or API keys. The proxy forwards them besides its allow list.
+func documentedHeaders(spec *openapi.Spec) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, item := range spec.Paths {
+		for _, method := range openapi.Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			for _, param := range op.Parameters {
+				if param.In == "header" {
+					add(param.Name)
+				}
+			}
+		}
+	}
+	if spec.Components != nil {
+		for _, scheme := range spec.Components.SecuritySchemes {
+			if scheme.Type == "apiKey" && scheme.In == "header" {
+				add(scheme.Name)
+			}
+		}
+	}
+	return names
+}
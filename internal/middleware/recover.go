@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "atomicdocs.requestID"
+
+// RequestID tags every request with an ID, reusing a well-formed one sent
+// by the client or a proxy, and echoes it in the response.
+func RequestID(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		id := string(ctx.Request.Header.Peek(RequestIDHeader))
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		ctx.SetUserValue(requestIDKey, id)
+		ctx.Response.Header.Set(RequestIDHeader, id)
+		next(ctx)
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// requestID returns the ID RequestID assigned to the request.
+func requestID(ctx *fasthttp.RequestCtx) string {
+	id, _ := ctx.UserValue(requestIDKey).(string)
+	return id
+}
+
+// requestLogger returns logger annotated with the request's ID.
+func requestLogger(logger *slog.Logger, ctx *fasthttp.RequestCtx) *slog.Logger {
+	if id := requestID(ctx); id != "" {
+		return logger.With("request_id", id)
+	}
+	return logger
+}
+
+// Recover turns a panic in a handler into a 500 response instead of
+// dropping the connection.
+func Recover(logger *slog.Logger) func(fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			defer func() {
+				if err := recover(); err != nil {
+					requestLogger(logger, ctx).Error("panic serving request",
+						"method", string(ctx.Method()),
+						"path", string(ctx.Path()),
+						"panic", err,
+						"stack", string(debug.Stack()))
+					ctx.ResetBody()
+					writeError(ctx, fasthttp.StatusInternalServerError, "internal server error")
+				}
+			}()
+			next(ctx)
+		}
+	}
+}
+
+// Logger logs one line per request with its status and duration. Requests
+// are logged at debug level, server errors at error level. Query strings
+// are left out since they can carry credentials.
+func Logger(logger *slog.Logger) func(fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			start := time.Now()
+			next(ctx)
+
+			status := ctx.Response.StatusCode()
+			level := slog.LevelDebug
+			if status >= fasthttp.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			requestLogger(logger, ctx).Log(ctx, level, "request",
+				"method", string(ctx.Method()),
+				"path", string(ctx.Path()),
+				"status", status,
+				"duration", time.Since(start).Round(time.Microsecond),
+				"remote_ip", ctx.RemoteIP().String())
+		}
+	}
+}
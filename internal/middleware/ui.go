@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/proxy"
+)
+
+// uiPage is what the docs page template needs to load an app's spec and
+// lint report, follow its registrations and send try-it requests through
+// the proxy.
+type uiPage struct {
+	App          string
+	SpecURL      string
+	LintURL      string
+	EventsURL    string
+	ProxyURL     string
+	TargetHeader string
+	AuthHeader   string
+}
+
+func (h *Handler) ServeUI(ctx *fasthttp.RequestCtx) {
+	app := appPort(ctx)
+	eventsURL := h.publicURL(ctx) + "/api/v1/apps/" + app + "/events"
+	if viewer := viewerFrom(ctx); viewer.Authenticated {
+		eventsURL += "?ticket=" + url.QueryEscape(h.tickets.Issue(app, viewer))
+	}
+	proxyURL := ""
+	if h.proxy.Enabled(h.config.Registration.Enabled()) {
+		proxyURL = h.publicURL(ctx) + "/api/v1/proxy/" + app
+	}
+	page := uiPage{
+		App:          app,
+		SpecURL:      "/docs/json?port=" + app,
+		LintURL:      h.publicURL(ctx) + "/api/v1/apps/" + app + "/lint",
+		EventsURL:    eventsURL,
+		ProxyURL:     proxyURL,
+		TargetHeader: proxy.TargetHeader,
+		AuthHeader:   proxy.AuthorizationHeader,
+	}
+
+	var buf bytes.Buffer
+	if err := swaggerTemplate.Execute(&buf, page); err != nil {
+		writeError(ctx, fasthttp.StatusInternalServerError, "failed to render docs page")
+		return
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	ctx.SetBody(buf.Bytes())
+}
+
+// publicURL is the server's base URL as the browser sees it. The framework
+// proxies only forward the docs pages, so the lint report, the event
+// stream and the try-it proxy are reached on the server directly.
+func (h *Handler) publicURL(ctx *fasthttp.RequestCtx) string {
+	if h.config.PublicURL != "" {
+		return strings.TrimSuffix(h.config.PublicURL, "/")
+	}
+	scheme := "http"
+	if ctx.IsTLS() {
+		scheme = "https"
+	}
+	return scheme + "://" + string(ctx.Host())
+}
+
+var swaggerTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>API Documentation</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.10.5/swagger-ui.css">
+    <style>
+        #lint { max-width: 1460px; margin: 16px auto 0; padding: 0 20px; font-family: sans-serif; font-size: 14px; }
+        #lint summary { cursor: pointer; font-weight: bold; }
+        #lint ul { list-style: none; padding: 0; }
+        #lint li { padding: 4px 0; border-bottom: 1px solid #eee; }
+        #lint .severity { display: inline-block; width: 64px; font-weight: bold; }
+        #lint .error { color: #c0392b; }
+        #lint .warning { color: #b9770e; }
+        #lint .info { color: #2471a3; }
+        #lint .rule { color: #888; }
+    </style>
+</head>
+<body>
+    <details id="lint" hidden>
+        <summary></summary>
+        <ul></ul>
+    </details>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5.10.5/swagger-ui-bundle.js"></script>
+    <script src="https://unpkg.com/swagger-ui-dist@5.10.5/swagger-ui-standalone-preset.js"></script>
+    <script>
+        const specURL = {{.SpecURL}};
+        const lintURL = {{.LintURL}};
+        const eventsURL = {{.EventsURL}};
+        const proxyURL = {{.ProxyURL}};
+        const targetHeader = {{.TargetHeader}};
+        const authHeader = {{.AuthHeader}};
+
+        // Send "Try it out" requests through the server's proxy, which
+        // works whatever the app's CORS policy is. The server the request
+        // was built for travels in a header; the proxy only accepts the
+        // servers the app registered. Without a proxy URL the proxy is
+        // disabled and requests go to the app directly. The request's
+        // Authorization moves to a header of its own, as the proxy never
+        // forwards the viewer's credentials for the docs.
+        const throughProxy = (req) => {
+            if (req.loadSpec || !proxyURL) {
+                return req;
+            }
+            const target = new URL(req.url, window.location.href);
+            req.headers[targetHeader] = target.origin;
+            for (const name of Object.keys(req.headers)) {
+                if (name.toLowerCase() === 'authorization') {
+                    req.headers[authHeader] = req.headers[name];
+                    delete req.headers[name];
+                }
+            }
+            req.url = proxyURL + target.pathname + target.search;
+            return req;
+        };
+
+        // Show the lint report above the docs. The panel stays hidden when
+        // the report cannot be loaded.
+        const showLint = () => {
+            const panel = document.getElementById('lint');
+            fetch(lintURL, { credentials: 'same-origin' })
+                .then(res => {
+                    if (!res.ok) {
+                        throw new Error('HTTP ' + res.status);
+                    }
+                    return res.json();
+                })
+                .then(report => {
+                    panel.querySelector('summary').textContent = 'Lint: ' + report.errors + ' errors, ' +
+                        report.warnings + ' warnings, ' + report.info + ' info';
+                    const list = panel.querySelector('ul');
+                    list.replaceChildren(...report.findings.map(f => {
+                        const item = document.createElement('li');
+                        const severity = document.createElement('span');
+                        severity.className = 'severity ' + f.severity;
+                        severity.textContent = f.severity;
+                        const rule = document.createElement('span');
+                        rule.className = 'rule';
+                        rule.textContent = ' [' + f.rule + ']';
+                        const where = [f.method, f.path].filter(Boolean).join(' ') || 'spec';
+                        item.append(severity, where + ': ' + f.message, rule);
+                        return item;
+                    }));
+                    panel.hidden = false;
+                })
+                .catch(err => {
+                    panel.hidden = true;
+                    console.warn('AtomicDocs: failed to load the lint report', err);
+                });
+        };
+
+        window.onload = () => {
+            const ui = SwaggerUIBundle({
+                url: specURL,
+                dom_id: '#swagger-ui',
+                presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+                layout: "BaseLayout",
+                deepLinking: true,
+                tryItOutEnabled: true,
+                requestInterceptor: throughProxy
+            });
+            window.ui = ui;
+            showLint();
+
+            if (!window.EventSource) {
+                return;
+            }
+
+            // Reload the spec in place when the app registers a new version.
+            // Expanded operations live in Swagger UI's layout state, which
+            // updateSpec keeps; the scroll position is restored by hand.
+            const events = new EventSource(eventsURL);
+            events.onerror = () => {
+                if (events.readyState === EventSource.CLOSED) {
+                    console.warn('AtomicDocs: live reload stopped; reload the page to resume it');
+                }
+            };
+            events.addEventListener('registered', () => {
+                const x = window.scrollX;
+                const y = window.scrollY;
+                fetch(specURL, { credentials: 'same-origin' })
+                    .then(res => {
+                        if (!res.ok) {
+                            throw new Error('HTTP ' + res.status);
+                        }
+                        return res.text();
+                    })
+                    .then(spec => {
+                        ui.specActions.updateSpec(spec);
+                        requestAnimationFrame(() => requestAnimationFrame(() => window.scrollTo(x, y)));
+                    })
+                    .catch(err => console.warn('AtomicDocs: failed to reload the spec', err));
+                showLint();
+            });
+        };
+    </script>
+</body>
+</html>`))
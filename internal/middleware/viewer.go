@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/atomicdocs/internal/auth"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+const viewerKey = "atomicdocs.viewer"
+
+// RequireViewer identifies the viewer and answers 401 when the app the
+// request is about is not visible to them. The viewer is stored on the
+// request for the handlers that filter what they return.
+func (h *Handler) RequireViewer(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		viewer := h.identify(ctx)
+		if !h.viewers.CanView(appPort(ctx), viewer) {
+			ctx.Response.Header.Set("WWW-Authenticate", h.viewers.Challenge())
+			writeError(ctx, fasthttp.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		ctx.SetUserValue(viewerKey, viewer)
+		next(ctx)
+	}
+}
+
+// RequireStreamViewer is RequireViewer for the event stream. Browsers cannot
+// set headers on an EventSource, so a "ticket" query argument issued with
+// the docs page identifies the viewer instead.
+func (h *Handler) RequireStreamViewer(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	withHeaders := h.RequireViewer(next)
+	return func(ctx *fasthttp.RequestCtx) {
+		ticket := ctx.QueryArgs().Peek("ticket")
+		if len(ticket) == 0 {
+			withHeaders(ctx)
+			return
+		}
+		viewer, ok := h.tickets.Verify(appPort(ctx), string(ticket))
+		if !ok || !h.viewers.CanView(appPort(ctx), viewer) {
+			writeError(ctx, fasthttp.StatusUnauthorized, "invalid or expired ticket")
+			return
+		}
+
+		ctx.SetUserValue(viewerKey, viewer)
+		next(ctx)
+	}
+}
+
+func (h *Handler) identify(ctx *fasthttp.RequestCtx) auth.Viewer {
+	return h.viewers.Identify(auth.ViewerRequest{
+		Authorization: string(ctx.Request.Header.Peek("Authorization")),
+		RemoteIP:      ctx.RemoteIP(),
+		Header: func(name string) string {
+			return string(ctx.Request.Header.Peek(name))
+		},
+	})
+}
+
+func viewerFrom(ctx *fasthttp.RequestCtx) auth.Viewer {
+	viewer, _ := ctx.UserValue(viewerKey).(auth.Viewer)
+	return viewer
+}
+
+// viewSpec builds the spec as the viewer is allowed to see it.
+func (h *Handler) viewSpec(viewer auth.Viewer, port string, routes []types.RouteInfo, meta types.AppMeta) *openapi.Spec {
+	spec := h.buildSpec(port, routes, meta)
+	if viewer.Authenticated || !h.viewers.Filters(port) {
+		return spec
+	}
+	return openapi.Filter(spec, func(method, path string, op *openapi.Operation) bool {
+		return !h.viewers.Hidden(port, viewer, method, path, op.Tags)
+	})
+}
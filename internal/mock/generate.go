@@ -0,0 +1,250 @@
+package mock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// maxDepth stops synthesising nested objects and arrays, so recursive
+// schemas end.
+const maxDepth = 8
+
+var (
+	firstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Dennis", "Barbara", "Ken", "Radia", "Edsger"}
+	lastNames  = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Ritchie", "Liskov", "Thompson", "Perlman", "Dijkstra"}
+	words      = []string{"alpha", "bravo", "delta", "echo", "harbor", "lumen", "nova", "orbit", "pixel", "quartz", "river", "summit", "tango", "vertex", "willow", "zephyr"}
+
+	baseTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Generator synthesises values from schemas. Values depend only on the
+// seed, so the same request gets the same data every time.
+type Generator struct {
+	rng     *rand.Rand
+	resolve func(types.Schema) types.Schema
+}
+
+// NewGenerator returns a generator for one response. The key, e.g. the
+// method, path and status, varies the data between requests that share a
+// seed.
+func NewGenerator(seed int64, key string, resolve func(types.Schema) types.Schema) *Generator {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return &Generator{
+		rng:     rand.New(rand.NewSource(seed ^ int64(h.Sum64()))),
+		resolve: resolve,
+	}
+}
+
+// Value synthesises a value for a schema. Examples and enums are used when
+// documented; otherwise the value follows the type, format and
+// constraints, and name, the property the value is for, hints at what
+// plain strings should look like.
+func (g *Generator) Value(schema types.Schema, name string) interface{} {
+	return g.value(schema, name, 0)
+}
+
+func (g *Generator) value(schema types.Schema, name string, depth int) interface{} {
+	if schema.Ref != "" && g.resolve != nil {
+		schema = g.resolve(schema)
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if len(schema.Enum) > 0 {
+		return enumValue(schema.Type, schema.Enum[g.rng.Intn(len(schema.Enum))])
+	}
+
+	typ := schema.Type
+	if typ == "" {
+		switch {
+		case schema.Properties != nil:
+			typ = "object"
+		case schema.Items != nil:
+			typ = "array"
+		default:
+			typ = "string"
+		}
+	}
+
+	switch typ {
+	case "object":
+		obj := map[string]interface{}{}
+		if depth >= maxDepth {
+			return obj
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for prop := range schema.Properties {
+			names = append(names, prop)
+		}
+		sort.Strings(names)
+		for _, prop := range names {
+			obj[prop] = g.value(schema.Properties[prop], prop, depth+1)
+		}
+		return obj
+	case "array":
+		items := []interface{}{}
+		if depth >= maxDepth || schema.Items == nil {
+			return items
+		}
+		n := 1 + g.rng.Intn(3)
+		if schema.MinItems != nil && n < *schema.MinItems {
+			n = *schema.MinItems
+		}
+		if schema.MaxItems != nil && n > *schema.MaxItems {
+			n = *schema.MaxItems
+		}
+		for i := 0; i < n; i++ {
+			items = append(items, g.value(*schema.Items, singular(name), depth+1))
+		}
+		return items
+	case "integer":
+		low, high := bounds(schema, 1, 1000)
+		span := int64(math.Floor(high)) - int64(math.Ceil(low))
+		if span <= 0 {
+			return int64(math.Ceil(low))
+		}
+		return int64(math.Ceil(low)) + g.rng.Int63n(span+1)
+	case "number":
+		low, high := bounds(schema, 0, 1000)
+		return math.Round((low+g.rng.Float64()*(high-low))*100) / 100
+	case "boolean":
+		return g.rng.Intn(2) == 0
+	default:
+		return g.str(schema, name)
+	}
+}
+
+// bounds returns the range numbers are drawn from, keeping the default
+// width when only one end is documented.
+func bounds(schema types.Schema, low, high float64) (float64, float64) {
+	switch {
+	case schema.Minimum != nil && schema.Maximum != nil:
+		return *schema.Minimum, math.Max(*schema.Minimum, *schema.Maximum)
+	case schema.Minimum != nil:
+		return *schema.Minimum, *schema.Minimum + (high - low)
+	case schema.Maximum != nil:
+		return *schema.Maximum - (high - low), *schema.Maximum
+	}
+	return low, high
+}
+
+func (g *Generator) str(schema types.Schema, name string) string {
+	format := schema.Format
+	if format == "" {
+		format = formatHint(name)
+	}
+
+	var s string
+	switch format {
+	case "date-time":
+		s = g.time().Format(time.RFC3339)
+	case "date":
+		s = g.time().Format("2006-01-02")
+	case "time":
+		s = g.time().Format("15:04:05")
+	case "email":
+		s = strings.ToLower(g.pick(firstNames)+"."+g.pick(lastNames)) + "@example.com"
+	case "uuid":
+		b := make([]byte, 16)
+		g.rng.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		s = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case "uri", "url":
+		s = "https://example.com/" + g.pick(words) + "/" + strconv.Itoa(g.rng.Intn(1000))
+	case "hostname":
+		s = g.pick(words) + ".example.com"
+	case "ipv4":
+		s = "192.0.2." + strconv.Itoa(1+g.rng.Intn(254))
+	case "ipv6":
+		s = "2001:db8::" + strconv.FormatInt(int64(1+g.rng.Intn(0xfffe)), 16)
+	case "byte":
+		b := make([]byte, 12)
+		g.rng.Read(b)
+		s = base64.StdEncoding.EncodeToString(b)
+	case "password":
+		s = "********"
+	case "name":
+		s = g.pick(firstNames) + " " + g.pick(lastNames)
+	case "phone":
+		s = fmt.Sprintf("+1-555-01%02d", g.rng.Intn(100))
+	default:
+		s = g.pick(words) + " " + g.pick(words)
+	}
+
+	if schema.MinLength != nil {
+		for len(s) < *schema.MinLength {
+			s += "x"
+		}
+	}
+	if schema.MaxLength != nil && len(s) > *schema.MaxLength {
+		s = s[:*schema.MaxLength]
+	}
+	return s
+}
+
+func (g *Generator) time() time.Time {
+	return baseTime.Add(time.Duration(g.rng.Int63n(int64(365 * 24 * time.Hour))))
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rng.Intn(len(values))]
+}
+
+// formatHint guesses what an unformatted string is from its property name.
+func formatHint(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "email"):
+		return "email"
+	case strings.HasSuffix(lower, "url") || strings.HasSuffix(lower, "uri") || strings.Contains(lower, "link"):
+		return "uri"
+	case strings.HasSuffix(lower, "edat") || strings.HasSuffix(lower, "ed_at"):
+		return "date-time"
+	case strings.Contains(lower, "date"):
+		return "date"
+	case strings.Contains(lower, "phone"):
+		return "phone"
+	case strings.Contains(lower, "password"):
+		return "password"
+	case lower == "id" || strings.HasSuffix(lower, "_id") || strings.HasSuffix(name, "Id"):
+		return "uuid"
+	case lower == "name" || lower == "fullname" || lower == "full_name":
+		return "name"
+	}
+	return ""
+}
+
+func singular(name string) string {
+	if strings.HasSuffix(name, "s") {
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// enumValue turns an enum entry, which is always a string, into a value
+// of the schema's type.
+func enumValue(typ, value string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
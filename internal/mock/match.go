@@ -0,0 +1,95 @@
+// Package mock answers requests from a registered spec: operations are
+// matched by method and path template, and responses are built from the
+// documented examples or synthesised from the schemas.
+package mock
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// Match is the operation a request was matched to.
+type Match struct {
+	Method    string
+	Path      string
+	Operation *openapi.Operation
+	Params    map[string]string
+}
+
+// Find matches a request to an operation of the spec. Templates may use
+// "{id}" or ":id" parameters and a trailing "*"; the template with the most
+// literal segments wins. When the path matches but the method does not,
+// the methods the path allows are returned instead.
+func Find(spec *openapi.Spec, method, path string) (*Match, []string) {
+	if method == "HEAD" {
+		method = "GET"
+	}
+	parts := split(path)
+
+	var best *Match
+	bestLiterals := -1
+	allowed := map[string]bool{}
+	for template, item := range spec.Paths {
+		params, literals, ok := matchTemplate(template, parts)
+		if !ok {
+			continue
+		}
+		op := item.Operation(method)
+		if op == nil {
+			for _, m := range openapi.Methods {
+				if item.Operation(m) != nil {
+					allowed[m] = true
+				}
+			}
+			continue
+		}
+		// Ties are broken by template so matching is deterministic.
+		if literals > bestLiterals || (literals == bestLiterals && template < best.Path) {
+			best = &Match{Method: method, Path: template, Operation: op, Params: params}
+			bestLiterals = literals
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+
+	methods := make([]string, 0, len(allowed))
+	for m := range allowed {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return nil, methods
+}
+
+func matchTemplate(template string, parts []string) (map[string]string, int, bool) {
+	segments := split(openapi.TemplatePath(template))
+	params := map[string]string{}
+	literals := 0
+	for i, seg := range segments {
+		if seg == "*" && i == len(segments)-1 {
+			return params, literals, true
+		}
+		if i >= len(parts) {
+			return nil, 0, false
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params[seg[1:len(seg)-1]] = parts[i]
+			continue
+		}
+		if !strings.EqualFold(seg, parts[i]) {
+			return nil, 0, false
+		}
+		literals++
+	}
+	return params, literals, len(parts) == len(segments)
+}
+
+func split(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,176 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+	"github.com/yourusername/atomicdocs/validate"
+)
+
+const (
+	// StatusHeader selects the documented response to return, e.g. "404".
+	StatusHeader = "X-Mock-Status"
+	// SeedHeader overrides the configured seed for one request.
+	SeedHeader = "X-Mock-Seed"
+	// ValidateHeader turns request validation on ("true") or off
+	// ("false") for one request.
+	ValidateHeader = "X-Mock-Validate"
+)
+
+// Response is a mocked response.
+type Response struct {
+	Status      int
+	ContentType string
+	Headers     map[string]string
+	Body        []byte
+}
+
+// Respond builds the response to a matched request. status selects the
+// documented response; without it the first documented success is used.
+// Bodies are the documented example when there is one and synthesised
+// from the schema otherwise, with fields named like a path parameter set
+// to its value. The same seed and request always yield the same response.
+func Respond(spec *openapi.Spec, m *Match, status string, seed int64, requestPath string) (*Response, error) {
+	code, documented, ok := selectResponse(m.Operation.Responses, status)
+	if !ok {
+		return nil, fmt.Errorf("status %s is not documented for %s %s", status, m.Method, m.Path)
+	}
+
+	gen := NewGenerator(seed, m.Method+" "+requestPath+" "+strconv.Itoa(code), spec.ResolveSchema)
+	resp := &Response{Status: code, Headers: map[string]string{}}
+
+	names := make([]string, 0, len(documented.Headers))
+	for name := range documented.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		resp.Headers[name] = fmt.Sprint(gen.Value(documented.Headers[name].Schema, name))
+	}
+
+	if code == http.StatusNoContent || code == http.StatusNotModified || len(documented.Content) == 0 {
+		return resp, nil
+	}
+
+	contentType := pickContentType(documented.Content)
+	media := documented.Content[contentType]
+	schema := spec.ResolveSchema(media.Schema)
+
+	body := media.Example
+	if body == nil {
+		body = gen.Value(schema, "")
+	}
+	body = echoParams(body, schema, m.Params)
+
+	resp.ContentType = contentType
+	if s, ok := body.(string); ok && !validate.IsJSON(contentType) {
+		resp.Body = []byte(s)
+		return resp, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the example: %w", err)
+	}
+	resp.Body = data
+	return resp, nil
+}
+
+// selectResponse finds the response for a requested status: the exact
+// code, then its class ("4XX"), then "default". Without a requested
+// status the lowest documented 2xx is used, then "default" as 200.
+func selectResponse(responses map[string]types.Response, requested string) (int, types.Response, bool) {
+	if requested != "" {
+		code, err := strconv.Atoi(requested)
+		if err != nil || code < 100 || code > 599 {
+			return 0, types.Response{}, false
+		}
+		if resp, ok := responses[requested]; ok {
+			return code, resp, true
+		}
+		for key, resp := range responses {
+			if len(key) == 3 && strings.EqualFold(key[1:], "xx") && key[0] == requested[0] {
+				return code, resp, true
+			}
+		}
+		resp, ok := responses["default"]
+		return code, resp, ok
+	}
+
+	codes := make([]string, 0, len(responses))
+	for key := range responses {
+		codes = append(codes, key)
+	}
+	sort.Strings(codes)
+	for _, key := range codes {
+		if strings.HasPrefix(key, "2") {
+			if code, err := strconv.Atoi(key); err == nil {
+				return code, responses[key], true
+			}
+			return http.StatusOK, responses[key], true
+		}
+	}
+	if resp, ok := responses["default"]; ok {
+		return http.StatusOK, resp, true
+	}
+	if len(codes) == 0 {
+		return http.StatusOK, types.Response{}, true
+	}
+	code, err := strconv.Atoi(codes[0])
+	if err != nil {
+		code = http.StatusOK
+	}
+	return code, responses[codes[0]], true
+}
+
+// pickContentType prefers JSON among the documented content types.
+func pickContentType(content map[string]types.MediaTypeObject) string {
+	names := make([]string, 0, len(content))
+	for contentType := range content {
+		names = append(names, contentType)
+	}
+	sort.Strings(names)
+	for _, contentType := range names {
+		if contentType == "application/json" {
+			return contentType
+		}
+	}
+	for _, contentType := range names {
+		if validate.IsJSON(contentType) {
+			return contentType
+		}
+	}
+	return names[0]
+}
+
+// echoParams sets the top-level fields of an object body that share a
+// name with a path parameter to the parameter's value, so GET /users/42
+// returns user 42. Examples are copied, not modified.
+func echoParams(body interface{}, schema types.Schema, params map[string]string) interface{} {
+	obj, ok := body.(map[string]interface{})
+	if !ok || len(params) == 0 {
+		return body
+	}
+	copied := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		copied[key] = value
+	}
+	for name, raw := range params {
+		if _, ok := copied[name]; !ok {
+			continue
+		}
+		propSchema, ok := schema.Properties[name]
+		if !ok {
+			propSchema = types.Schema{Type: "string"}
+		}
+		if value, ok := validate.CoerceParam(propSchema, []string{raw}); ok {
+			copied[name] = value
+		}
+	}
+	return copied
+}
@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseDocument decodes an OpenAPI document written in JSON or YAML into
+// its raw tree: objects are map[string]interface{}, arrays []interface{},
+// and numbers json.Number. Unlike Spec, the tree keeps everything the
+// document says, such as references to parameters and 3.1 type arrays.
+func ParseDocument(data []byte) (map[string]interface{}, error) {
+	var value interface{}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("unexpected data after the JSON document")
+		}
+	} else {
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, err
+		}
+		var err error
+		if value, err = fromYAML(&root); err != nil {
+			return nil, err
+		}
+	}
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the document is not an object")
+	}
+	return doc, nil
+}
+
+// fromYAML converts a YAML node to the values encoding/json decodes to.
+// Keys are kept as written, so unquoted status codes such as 200 stay
+// strings.
+func fromYAML(n *yaml.Node) (interface{}, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return fromYAML(n.Content[0])
+	case yaml.AliasNode:
+		return fromYAML(n.Alias)
+	case yaml.MappingNode:
+		obj := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: keys must be strings", key.Line)
+			}
+			value, err := fromYAML(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			obj[key.Value] = value
+		}
+		return obj, nil
+	case yaml.SequenceNode:
+		items := make([]interface{}, len(n.Content))
+		for i, item := range n.Content {
+			value, err := fromYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	}
+
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case "!!int", "!!float":
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return nil, err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("line %d: %s is not a JSON number", n.Line, n.Value)
+		}
+		// Written back as JSON, since YAML also allows 0x1F, +1 and 1_000.
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64)), nil
+	}
+	return n.Value, nil
+}
@@ -0,0 +1,79 @@
+package openapi
+
+import "github.com/yourusername/atomicdocs/internal/types"
+
+// Filter returns a copy of the spec holding only the operations keep accepts.
+// Request schemas generated for dropped operations are removed from the
+// components, and so are declared tags no remaining operation uses.
+func Filter(spec *Spec, keep func(method, path string, op *Operation) bool) *Spec {
+	filtered := *spec
+	filtered.Paths = make(map[string]PathItem, len(spec.Paths))
+
+	dropped := make(map[string]bool)
+	usedTags := make(map[string]bool)
+
+	for path, item := range spec.Paths {
+		var kept PathItem
+		empty := true
+		for _, method := range Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			if !keep(method, path, op) {
+				dropped[sanitizeSchemaName(method+path)] = true
+				continue
+			}
+			kept.set(method, op)
+			empty = false
+			for _, tag := range op.Tags {
+				usedTags[tag] = true
+			}
+		}
+		if !empty {
+			filtered.Paths[path] = kept
+		}
+	}
+
+	if len(dropped) == 0 {
+		filtered.Paths = spec.Paths
+		return &filtered
+	}
+
+	if spec.Components != nil && len(spec.Components.Schemas) > 0 {
+		components := *spec.Components
+		components.Schemas = make(map[string]types.Schema, len(spec.Components.Schemas))
+		for name, schema := range spec.Components.Schemas {
+			if !dropped[name] {
+				components.Schemas[name] = schema
+			}
+		}
+		filtered.Components = &components
+	}
+
+	if len(spec.Tags) > 0 {
+		filtered.Tags = nil
+		for _, tag := range spec.Tags {
+			if usedTags[tag.Name] {
+				filtered.Tags = append(filtered.Tags, tag)
+			}
+		}
+	}
+
+	return &filtered
+}
+
+func (p *PathItem) set(method string, op *Operation) {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "POST":
+		p.Post = op
+	case "PUT":
+		p.Put = op
+	case "DELETE":
+		p.Delete = op
+	case "PATCH":
+		p.Patch = op
+	}
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// MergeMeta layers the metadata an app registered on top of the server
+// defaults. Info fields are merged one by one; servers and tags from the app
+// replace the defaults as a whole when present.
+func MergeMeta(defaults, app types.AppMeta) types.AppMeta {
+	merged := defaults
+
+	if app.Info != nil {
+		if merged.Info == nil {
+			info := *app.Info
+			merged.Info = &info
+		} else {
+			info := *merged.Info
+			if app.Info.Title != "" {
+				info.Title = app.Info.Title
+			}
+			if app.Info.Description != "" {
+				info.Description = app.Info.Description
+			}
+			if app.Info.TermsOfService != "" {
+				info.TermsOfService = app.Info.TermsOfService
+			}
+			if app.Info.Contact != nil {
+				info.Contact = app.Info.Contact
+			}
+			if app.Info.License != nil {
+				info.License = app.Info.License
+			}
+			if app.Info.Version != "" {
+				info.Version = app.Info.Version
+			}
+			merged.Info = &info
+		}
+	}
+
+	if len(app.Servers) > 0 {
+		merged.Servers = app.Servers
+	}
+	if len(app.Tags) > 0 {
+		merged.Tags = app.Tags
+	}
+
+	return merged
+}
+
+// orderTags keeps declared tags in the order they were given and appends any
+// tag used by an operation but not declared, sorted by name. Without declared
+// tags the spec leaves ordering to the UI.
+func orderTags(declared []types.Tag, routes []types.RouteInfo) []types.Tag {
+	if len(declared) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	tags := make([]types.Tag, 0, len(declared))
+	for _, tag := range declared {
+		if tag.Name == "" || seen[tag.Name] {
+			continue
+		}
+		seen[tag.Name] = true
+		tags = append(tags, tag)
+	}
+
+	var extra []string
+	for _, route := range routes {
+		for _, name := range route.Tags {
+			if !seen[name] {
+				seen[name] = true
+				extra = append(extra, name)
+			}
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		tags = append(tags, types.Tag{Name: name})
+	}
+
+	return tags
+}
+
+// maxServerURLs bounds how many URLs a server with enum variables expands
+// to.
+const maxServerURLs = 64
+
+// ServerURLs lists the concrete base URLs of the servers, without trailing
+// slashes. Variables are replaced by each of their enum values, or by
+// their default when they have none; relative URLs are resolved against
+// base.
+func ServerURLs(servers []types.Server, base string) []string {
+	var urls []string
+	for _, server := range servers {
+		expanded := []string{server.URL}
+		names := make([]string, 0, len(server.Variables))
+		for name := range server.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			variable := server.Variables[name]
+			values := variable.Enum
+			if len(values) == 0 {
+				values = []string{variable.Default}
+			}
+			var next []string
+			for _, url := range expanded {
+				for _, value := range values {
+					if len(next) < maxServerURLs {
+						next = append(next, strings.ReplaceAll(url, "{"+name+"}", value))
+					}
+				}
+			}
+			expanded = next
+		}
+		for _, url := range expanded {
+			if strings.HasPrefix(url, "/") {
+				url = strings.TrimSuffix(base, "/") + url
+			}
+			urls = append(urls, strings.TrimSuffix(url, "/"))
+		}
+	}
+	return urls
+}
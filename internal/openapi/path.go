@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	colonParam = regexp.MustCompile(`:(\w+)`)
+	braceParam = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// TemplatePath rewrites Express/Fiber style ":id" segments to the OpenAPI
+// "{id}" form. Paths already using braces are returned unchanged.
+func TemplatePath(path string) string {
+	return colonParam.ReplaceAllString(path, "{$1}")
+}
+
+// PathParams returns the parameter names in a path template, accepting both
+// ":id" and "{id}" segments.
+func PathParams(path string) []string {
+	var names []string
+	for _, match := range braceParam.FindAllStringSubmatch(TemplatePath(path), -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// PathKey drops parameter names so templates that only differ in naming,
+// such as /users/:id and /users/{userId}, compare equal.
+func PathKey(path string) string {
+	return strings.ToLower(braceParam.ReplaceAllString(TemplatePath(path), "{}"))
+}
@@ -1,28 +1,20 @@
 package openapi
 
 import (
+	"strings"
+
 	"github.com/yourusername/atomicdocs/internal/types"
 )
 
 type Spec struct {
 	OpenAPI    string                `json:"openapi"`
-	Info       Info                  `json:"info"`
-	Servers    []Server              `json:"servers"`
+	Info       types.Info            `json:"info"`
+	Servers    []types.Server        `json:"servers"`
+	Tags       []types.Tag           `json:"tags,omitempty"`
 	Paths      map[string]PathItem   `json:"paths"`
 	Components *Components           `json:"components,omitempty"`
 }
 
-type Info struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Version     string `json:"version"`
-}
-
-type Server struct {
-	URL         string `json:"url"`
-	Description string `json:"description,omitempty"`
-}
-
 type Components struct {
 	Schemas         map[string]types.Schema         `json:"schemas,omitempty"`
 	SecuritySchemes map[string]types.SecurityScheme `json:"securitySchemes,omitempty"`
@@ -37,6 +29,7 @@ type PathItem struct {
 }
 
 type Operation struct {
+	OperationID string                       `json:"operationId,omitempty"`
 	Summary     string                       `json:"summary,omitempty"`
 	Description string                       `json:"description,omitempty"`
 	Tags        []string                     `json:"tags,omitempty"`
@@ -44,9 +37,13 @@ type Operation struct {
 	RequestBody *types.RequestBody           `json:"requestBody,omitempty"`
 	Responses   map[string]types.Response    `json:"responses"`
 	Security    []types.SecurityRequirement  `json:"security,omitempty"`
+
+	// Provenance is set on operations whose schemas were not written by
+	// hand, e.g. learned from traffic.
+	Provenance *types.Provenance `json:"x-atomicdocs-provenance,omitempty"`
 }
 
-func Generate(routes []types.RouteInfo, baseURL string) *Spec {
+func Generate(routes []types.RouteInfo, baseURL string, meta types.AppMeta) *Spec {
 	paths := make(map[string]PathItem)
 	schemas := make(map[string]types.Schema)
 	securitySchemes := make(map[string]types.SecurityScheme)
@@ -55,6 +52,7 @@ func Generate(routes []types.RouteInfo, baseURL string) *Spec {
 		item := paths[route.Path]
 		
 		op := &Operation{
+			OperationID: route.OperationID,
 			Summary:     route.Summary,
 			Description: route.Description,
 			Tags:        route.Tags,
@@ -62,6 +60,7 @@ func Generate(routes []types.RouteInfo, baseURL string) *Spec {
 			RequestBody: route.RequestBody,
 			Responses:   route.Responses,
 			Security:    route.Security,
+			Provenance:  route.Provenance,
 		}
 		
 		if op.Responses == nil {
@@ -106,18 +105,7 @@ func Generate(routes []types.RouteInfo, baseURL string) *Spec {
 			}
 		}
 		
-		switch route.Method {
-		case "GET":
-			item.Get = op
-		case "POST":
-			item.Post = op
-		case "PUT":
-			item.Put = op
-		case "DELETE":
-			item.Delete = op
-		case "PATCH":
-			item.Patch = op
-		}
+		item.set(route.Method, op)
 		paths[route.Path] = item
 	}
 	
@@ -132,14 +120,31 @@ func Generate(routes []types.RouteInfo, baseURL string) *Spec {
 		}
 	}
 	
+	info := types.Info{
+		Title:       "API Documentation",
+		Description: "Auto-generated API documentation",
+		Version:     "1.0.0",
+	}
+	if meta.Info != nil {
+		info = *meta.Info
+		if info.Title == "" {
+			info.Title = "API Documentation"
+		}
+		if info.Version == "" {
+			info.Version = "1.0.0"
+		}
+	}
+	
+	servers := meta.Servers
+	if len(servers) == 0 {
+		servers = []types.Server{{URL: baseURL}}
+	}
+	
 	return &Spec{
-		OpenAPI: "3.0.0",
-		Info: Info{
-			Title:       "API Documentation",
-			Description: "Auto-generated API documentation",
-			Version:     "1.0.0",
-		},
-		Servers:    []Server{{URL: baseURL}},
+		OpenAPI:    "3.0.0",
+		Info:       info,
+		Servers:    servers,
+		Tags:       orderTags(meta.Tags, routes),
 		Paths:      paths,
 		Components: components,
 	}
@@ -154,3 +159,41 @@ func sanitizeSchemaName(name string) string {
 	}
 	return result
 }
+
+// Methods lists the HTTP methods a PathItem can hold, in the order they are
+// usually presented.
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// Operation returns the operation registered for method, or nil.
+func (p PathItem) Operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "DELETE":
+		return p.Delete
+	case "PATCH":
+		return p.Patch
+	}
+	return nil
+}
+
+// ResolveSchema follows a local "#/components/schemas/..." reference. Schemas
+// without a reference, or with one that cannot be resolved, are returned as is.
+func (s *Spec) ResolveSchema(schema types.Schema) types.Schema {
+	const prefix = "#/components/schemas/"
+	for i := 0; schema.Ref != "" && i < 32; i++ {
+		if s.Components == nil || !strings.HasPrefix(schema.Ref, prefix) {
+			return schema
+		}
+		target, ok := s.Components.Schemas[strings.TrimPrefix(schema.Ref, prefix)]
+		if !ok {
+			return schema
+		}
+		schema = target
+	}
+	return schema
+}
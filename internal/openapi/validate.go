@@ -0,0 +1,554 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// Problem is a place where a spec breaks the OpenAPI rules. Pointer is a
+// JSON pointer to it, e.g. "/paths/~1users~1{id}/get/responses".
+type Problem struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// Validate checks the structure of a generated spec; see ValidateDocument.
+// It returns nil for a valid spec.
+func Validate(spec *Spec) []Problem {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return []Problem{{Message: "the spec cannot be encoded: " + err.Error()}}
+	}
+	doc, err := ParseDocument(data)
+	if err != nil {
+		return []Problem{{Message: "the spec cannot be decoded: " + err.Error()}}
+	}
+	return ValidateDocument(doc)
+}
+
+// ValidateDocument checks the structure of a document, as ParseDocument
+// returns it, against OpenAPI 3.0 and 3.1: the required fields, that path
+// templates and path parameters agree, that operation IDs and parameters
+// are unique, that every local reference resolves and every security
+// scheme is defined, and that schemas only use their version's keywords.
+// Path-level parameters count for every operation of the path. References
+// to other files are not followed. It returns nil for a valid document.
+func ValidateDocument(doc map[string]interface{}) []Problem {
+	v := &validator{doc: doc, operationIDs: map[string]string{}}
+
+	version, _ := doc["openapi"].(string)
+	switch {
+	case doc["openapi"] == nil:
+		v.add("/openapi", "the OpenAPI version is required")
+	case version == "":
+		v.add("/openapi", "the OpenAPI version must be a string, such as \"3.0.3\"")
+	case strings.HasPrefix(version, "3.1."):
+		v.v31 = true
+	case !strings.HasPrefix(version, "3.0."):
+		v.add("/openapi", "OpenAPI version %q is not 3.0 or 3.1", version)
+	}
+
+	if info := v.object("/info", doc["info"]); info == nil {
+		v.add("/info", "info is required")
+	} else {
+		v.text("/info/title", info["title"], "the title")
+		v.text("/info/version", info["version"], "the version")
+	}
+	// 3.1 documents may hold only components, such as a shared schema library.
+	if doc["paths"] == nil && !(v.v31 && (doc["components"] != nil || doc["webhooks"] != nil)) {
+		v.add("/paths", "paths is required")
+	}
+	for i, raw := range v.array("/servers", doc["servers"]) {
+		at := fmt.Sprintf("/servers/%d", i)
+		if server := v.object(at, raw); server != nil {
+			v.text(at+"/url", server["url"], "the server URL")
+		}
+	}
+	tags := map[string]bool{}
+	for i, raw := range v.array("/tags", doc["tags"]) {
+		at := fmt.Sprintf("/tags/%d", i)
+		tag := v.object(at, raw)
+		if tag == nil {
+			continue
+		}
+		name, _ := tag["name"].(string)
+		if tags[name] {
+			v.add(at+"/name", "tag %q is declared twice", name)
+		}
+		tags[name] = true
+	}
+	v.security("/security", doc["security"])
+
+	paths := v.object("/paths", doc["paths"])
+	templates := map[string]string{}
+	for _, path := range sortedKeys(paths) {
+		at := "/paths/" + pointerEscape(path)
+		if strings.HasPrefix(path, "x-") {
+			continue
+		}
+		if !strings.HasPrefix(path, "/") {
+			v.add(at, "path %q does not start with /", path)
+		}
+		if colonParam.MatchString(path) {
+			v.add(at, "path %q uses :name segments; OpenAPI templates are written {name}, as in %q", path, TemplatePath(path))
+		}
+		if other, ok := templates[PathKey(path)]; ok {
+			v.add(at, "path %q is the same template as %q", path, other)
+		} else {
+			templates[PathKey(path)] = path
+		}
+		v.pathItem(at, path, paths[path])
+	}
+
+	components := v.object("/components", doc["components"])
+	for _, kind := range sortedKeys(components) {
+		if strings.HasPrefix(kind, "x-") {
+			continue
+		}
+		entries := v.object("/components/"+pointerEscape(kind), components[kind])
+		for _, name := range sortedKeys(entries) {
+			at := "/components/" + pointerEscape(kind) + "/" + pointerEscape(name)
+			if !componentName.MatchString(name) {
+				v.add(at, "component name %q may only hold letters, digits, \".\", \"-\" and \"_\"", name)
+			}
+			v.component(at, kind, entries[name])
+		}
+	}
+	return v.problems
+}
+
+// ValidateRoutes reports the registered routes Generate leaves out of the
+// spec: routes with methods a path item cannot hold, and routes registered
+// twice, of which the last is kept. HEAD routes of paths with a GET are
+// not reported: frameworks such as Fiber add them for every GET, and the
+// GET documents them.
+func ValidateRoutes(routes []types.RouteInfo) []Problem {
+	var problems []Problem
+	gets := map[string]bool{}
+	for _, route := range routes {
+		if route.Method == "GET" {
+			gets[route.Path] = true
+		}
+	}
+	seen := map[string]bool{}
+	for _, route := range routes {
+		at := "/paths/" + pointerEscape(route.Path) + "/" + strings.ToLower(route.Method)
+		switch {
+		case route.Method == "HEAD" && gets[route.Path]:
+		case !supportedMethod(route.Method):
+			problems = append(problems, Problem{Pointer: at, Message: fmt.Sprintf("method %q of %s is not documented; only %s are", route.Method, route.Path, strings.Join(Methods, ", "))})
+		case seen[route.Method+" "+route.Path]:
+			problems = append(problems, Problem{Pointer: at, Message: fmt.Sprintf("%s %s is registered more than once; the last registration is documented", route.Method, route.Path)})
+		}
+		seen[route.Method+" "+route.Path] = true
+	}
+	return problems
+}
+
+func supportedMethod(method string) bool {
+	for _, m := range Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	componentName = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	statusCode    = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX|default)$`)
+)
+
+// pathMethods lists the operations a path item can hold.
+var pathMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type validator struct {
+	doc      map[string]interface{}
+	v31      bool
+	problems []Problem
+
+	// operationIDs maps the operation IDs seen to where they were.
+	operationIDs map[string]string
+}
+
+func (v *validator) add(pointer, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// object returns raw as an object, reporting it when it is something else.
+// It returns nil for a missing value.
+func (v *validator) object(at string, raw interface{}) map[string]interface{} {
+	obj, ok := raw.(map[string]interface{})
+	if !ok && raw != nil {
+		v.add(at, "must be an object")
+	}
+	return obj
+}
+
+// array returns raw as an array, reporting it when it is something else.
+func (v *validator) array(at string, raw interface{}) []interface{} {
+	items, ok := raw.([]interface{})
+	if !ok && raw != nil {
+		v.add(at, "must be an array")
+	}
+	return items
+}
+
+// text reports a required string field that is missing or not a string.
+func (v *validator) text(at string, raw interface{}, what string) {
+	switch s, ok := raw.(string); {
+	case raw == nil || (ok && s == ""):
+		v.add(at, "%s is required", what)
+	case !ok:
+		v.add(at, "%s must be a string", what)
+	}
+}
+
+// resolve follows the references of obj, reporting at at those that do
+// not resolve. It reports false for them, and for references to other
+// files, which are not followed.
+func (v *validator) resolve(at string, obj map[string]interface{}) (map[string]interface{}, bool) {
+	for depth := 0; ; depth++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj, true
+		}
+		if !strings.HasPrefix(ref, "#") {
+			return nil, false
+		}
+		if depth == 32 {
+			v.add(at+"/$ref", "reference %q is circular", ref)
+			return nil, false
+		}
+		target, ok := v.lookup(ref).(map[string]interface{})
+		if !ok {
+			v.add(at+"/$ref", "reference %q does not resolve", ref)
+			return nil, false
+		}
+		obj = target
+	}
+}
+
+// lookup returns the value a local reference such as
+// "#/components/schemas/User" points to, or nil.
+func (v *validator) lookup(ref string) interface{} {
+	pointer := strings.TrimPrefix(ref, "#")
+	var value interface{} = v.doc
+	if pointer == "" {
+		return value
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := value.(type) {
+		case map[string]interface{}:
+			value = node[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			value = node[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+func (v *validator) pathItem(at, path string, raw interface{}) {
+	item := v.object(at, raw)
+	if item == nil {
+		return
+	}
+	if _, ok := item["$ref"]; ok {
+		v.resolve(at, item)
+		return
+	}
+	shared := v.parameters(at+"/parameters", path, item["parameters"])
+	empty := true
+	for _, method := range pathMethods {
+		if op, ok := item[method]; ok {
+			empty = false
+			v.operation(at+"/"+method, path, op, shared)
+		}
+	}
+	if empty {
+		v.add(at, "path %q has no operations", path)
+	}
+}
+
+// param is a parameter as it counts for the operations that use it.
+type param struct {
+	name, in string
+}
+
+// parameters checks a list of parameters of a path or an operation, and
+// returns the ones it could resolve.
+func (v *validator) parameters(at, path string, raw interface{}) []param {
+	inTemplate := map[string]bool{}
+	for _, name := range PathParams(path) {
+		inTemplate[name] = true
+	}
+	unique := map[string]bool{}
+	var params []param
+	for i, item := range v.array(at, raw) {
+		paramAt := fmt.Sprintf("%s/%d", at, i)
+		obj := v.object(paramAt, item)
+		if obj == nil {
+			continue
+		}
+		if _, ok := obj["$ref"]; ok {
+			if obj, ok = v.resolve(paramAt, obj); !ok {
+				continue
+			}
+		} else {
+			v.parameter(paramAt, obj)
+		}
+		p := param{name: stringValue(obj["name"]), in: stringValue(obj["in"])}
+		if p.name == "" {
+			continue
+		}
+		// Header names are case-insensitive.
+		key := p.in + " " + p.name
+		if p.in == "header" {
+			key = strings.ToLower(key)
+		}
+		if unique[key] {
+			v.add(paramAt, "%s parameter %q is declared twice", p.in, p.name)
+		}
+		unique[key] = true
+		if p.in == "path" && !inTemplate[p.name] {
+			v.add(paramAt, "path parameter %q is not in the path", p.name)
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// parameter checks a parameter where it is written out.
+func (v *validator) parameter(at string, p map[string]interface{}) {
+	name, in := stringValue(p["name"]), stringValue(p["in"])
+	switch {
+	case name == "":
+		v.add(at+"/name", "the parameter name is required")
+	case in != "path" && in != "query" && in != "header" && in != "cookie":
+		v.add(at+"/in", "parameter %q is in %q, not path, query, header or cookie", name, in)
+	case in == "path" && p["required"] != true:
+		v.add(at+"/required", "path parameter %q must be required", name)
+	}
+	if s, ok := p["schema"]; ok {
+		v.schema(at+"/schema", s)
+	}
+	v.content(at+"/content", p["content"])
+}
+
+func (v *validator) operation(at, path string, raw interface{}, shared []param) {
+	op := v.object(at, raw)
+	if op == nil {
+		return
+	}
+	if id := stringValue(op["operationId"]); id != "" {
+		if other, ok := v.operationIDs[id]; ok {
+			v.add(at+"/operationId", "operation ID %q is also used by %s", id, other)
+		} else {
+			v.operationIDs[id] = at
+		}
+	}
+
+	// Every parameter in the template is declared, on the path or on the
+	// operation.
+	declared := map[string]bool{}
+	for _, p := range append(shared, v.parameters(at+"/parameters", path, op["parameters"])...) {
+		if p.in == "path" {
+			declared[p.name] = true
+		}
+	}
+	for _, name := range PathParams(path) {
+		if !declared[name] {
+			v.add(at+"/parameters", "path parameter %q is not declared", name)
+		}
+	}
+
+	if body := v.object(at+"/requestBody", op["requestBody"]); body != nil {
+		v.component(at+"/requestBody", "requestBodies", body)
+	}
+
+	responses := v.object(at+"/responses", op["responses"])
+	if len(responses) == 0 {
+		v.add(at+"/responses", "at least one response is required")
+	}
+	for _, status := range sortedKeys(responses) {
+		respAt := at + "/responses/" + pointerEscape(status)
+		if strings.HasPrefix(status, "x-") {
+			continue
+		}
+		if !statusCode.MatchString(status) {
+			v.add(respAt, "response status %q is not a status code, a range such as 4XX, or default", status)
+		}
+		v.component(respAt, "responses", responses[status])
+	}
+
+	v.security(at+"/security", op["security"])
+}
+
+// component checks a value of one of the kinds components can hold,
+// wherever it is written. References are only checked to resolve: what
+// they point to is checked where it is written.
+func (v *validator) component(at, kind string, raw interface{}) {
+	if kind == "schemas" {
+		v.schema(at, raw)
+		return
+	}
+	obj := v.object(at, raw)
+	if obj == nil {
+		return
+	}
+	if _, ok := obj["$ref"]; ok {
+		v.resolve(at, obj)
+		return
+	}
+	switch kind {
+	case "parameters":
+		v.parameter(at, obj)
+	case "requestBodies":
+		content := v.object(at+"/content", obj["content"])
+		if len(content) == 0 {
+			v.add(at+"/content", "the request body has no content")
+		}
+		v.content(at+"/content", content)
+	case "responses":
+		if stringValue(obj["description"]) == "" {
+			v.add(at+"/description", "the response description is required")
+		}
+		headers := v.object(at+"/headers", obj["headers"])
+		for _, name := range sortedKeys(headers) {
+			v.component(at+"/headers/"+pointerEscape(name), "headers", headers[name])
+		}
+		v.content(at+"/content", obj["content"])
+	case "headers":
+		if s, ok := obj["schema"]; ok {
+			v.schema(at+"/schema", s)
+		}
+		v.content(at+"/content", obj["content"])
+	}
+}
+
+func (v *validator) content(at string, raw interface{}) {
+	content := v.object(at, raw)
+	for _, name := range sortedKeys(content) {
+		mediaAt := at + "/" + pointerEscape(name)
+		if media := v.object(mediaAt, content[name]); media != nil {
+			if s, ok := media["schema"]; ok {
+				v.schema(mediaAt+"/schema", s)
+			}
+		}
+	}
+}
+
+func (v *validator) security(at string, raw interface{}) {
+	schemes, _ := v.lookup("#/components/securitySchemes").(map[string]interface{})
+	for i, item := range v.array(at, raw) {
+		requirementAt := fmt.Sprintf("%s/%d", at, i)
+		requirement := v.object(requirementAt, item)
+		for _, name := range sortedKeys(requirement) {
+			if _, ok := schemes[name]; !ok {
+				v.add(requirementAt+"/"+pointerEscape(name), "security scheme %q is not defined in components", name)
+			}
+		}
+	}
+}
+
+// schemaTypes are the types a schema can have; 3.1 adds "null".
+var schemaTypes = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true}
+
+// schema checks that the references in a schema resolve, and that it only
+// uses keywords of the document's version.
+func (v *validator) schema(at string, raw interface{}) {
+	if _, ok := raw.(bool); ok && v.v31 {
+		return
+	}
+	s := v.object(at, raw)
+	if s == nil {
+		return
+	}
+	if ref, ok := s["$ref"].(string); ok && strings.HasPrefix(ref, "#") && v.lookup(ref) == nil {
+		v.add(at+"/$ref", "reference %q does not resolve", ref)
+	}
+
+	switch typ := s["type"].(type) {
+	case nil:
+	case string:
+		v.schemaType(at+"/type", typ)
+	case []interface{}:
+		if !v.v31 {
+			v.add(at+"/type", "type arrays need OpenAPI 3.1; in 3.0 use a single type and nullable: true")
+			break
+		}
+		for i, item := range typ {
+			v.schemaType(fmt.Sprintf("%s/type/%d", at, i), stringValue(item))
+		}
+	default:
+		v.add(at+"/type", "type must be a string or an array of strings")
+	}
+	if _, ok := s["nullable"]; ok && v.v31 {
+		v.add(at+"/nullable", "nullable is not an OpenAPI 3.1 keyword; add \"null\" to the type instead, as in type: [string, \"null\"]")
+	}
+
+	props := v.object(at+"/properties", s["properties"])
+	for _, name := range sortedKeys(props) {
+		v.schema(at+"/properties/"+pointerEscape(name), props[name])
+	}
+	for _, keyword := range []string{"items", "not"} {
+		if sub, ok := s[keyword]; ok {
+			v.schema(at+"/"+keyword, sub)
+		}
+	}
+	if sub, ok := s["additionalProperties"]; ok {
+		if _, isBool := sub.(bool); !isBool {
+			v.schema(at+"/additionalProperties", sub)
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		for i, sub := range v.array(at+"/"+keyword, s[keyword]) {
+			v.schema(fmt.Sprintf("%s/%s/%d", at, keyword, i), sub)
+		}
+	}
+}
+
+func (v *validator) schemaType(at, typ string) {
+	if !schemaTypes[typ] && !(v.v31 && typ == "null") {
+		v.add(at, "%q is not a schema type", typ)
+	}
+}
+
+func stringValue(raw interface{}) string {
+	s, _ := raw.(string)
+	return s
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pointerEscape escapes a key for a JSON pointer.
+func pointerEscape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
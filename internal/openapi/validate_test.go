@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+func readDocument(t *testing.T, name string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ParseDocument(data)
+	if err != nil {
+		t.Fatalf("ParseDocument(%s) = %v", name, err)
+	}
+	return doc
+}
+
+func TestValidateDocumentReferences(t *testing.T) {
+	// Parameters, responses and request bodies by reference, path-level
+	// parameters, unquoted status codes and YAML anchors.
+	if problems := ValidateDocument(readDocument(t, "refs.yaml")); len(problems) != 0 {
+		t.Errorf("ValidateDocument(refs.yaml) = %+v, want none", problems)
+	}
+}
+
+func TestValidateDocumentTypeArrays(t *testing.T) {
+	if problems := ValidateDocument(readDocument(t, "types.json")); len(problems) != 0 {
+		t.Errorf("ValidateDocument(types.json) = %+v, want none", problems)
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	doc := readDocument(t, "refs.yaml")
+	responses := doc["paths"].(map[string]interface{})["/pets/{petId}"].(map[string]interface{})["get"].(map[string]interface{})["responses"].(map[string]interface{})
+	if _, ok := responses["200"]; !ok {
+		t.Errorf("responses = %v, want the status code 200 as a key", responses)
+	}
+	if got := doc["info"].(map[string]interface{})["version"]; got != "1.0.0" {
+		t.Errorf("info.version = %#v, want \"1.0.0\"", got)
+	}
+
+	numbers, err := ParseDocument([]byte("a: 0x10\nb: 1_000\nc: 1.5\nd: '7'\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": json.Number("16"), "b": json.Number("1000"), "c": json.Number("1.5"), "d": "7"}
+	if !reflect.DeepEqual(numbers, want) {
+		t.Errorf("ParseDocument() = %#v, want %#v", numbers, want)
+	}
+
+	for _, bad := range []string{"[1, 2]", "{", "a: .inf"} {
+		if _, err := ParseDocument([]byte(bad)); err == nil {
+			t.Errorf("ParseDocument(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestValidateDocumentProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []string // pointer: message prefix
+	}{
+		{
+			"missing fields",
+			`openapi: 3.0.3
+info: {}`,
+			[]string{"/info/title: the title is required", "/info/version: the version is required", "/paths: paths is required"},
+		},
+		{
+			"numeric version",
+			`{"openapi": 3.1, "info": {"title": "T", "version": 1}, "paths": {}}`,
+			[]string{"/openapi: the OpenAPI version must be a string", "/info/version: the version must be a string"},
+		},
+		{
+			"3.1 components only",
+			`{"openapi": "3.1.0", "info": {"title": "T", "version": "1"}, "components": {"schemas": {"A": {"type": "string"}}}}`,
+			nil,
+		},
+		{
+			"dangling references",
+			`openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /a:
+    post:
+      parameters:
+        - $ref: '#/components/parameters/Missing'
+      requestBody:
+        $ref: '#/components/requestBodies/Missing'
+      responses:
+        '200':
+          $ref: '#/components/responses/Missing'
+        '400':
+          description: Bad
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Missing'
+        '500':
+          $ref: 'errors.yaml#/Error'`,
+			[]string{
+				"/paths/~1a/post/parameters/0/$ref: reference \"#/components/parameters/Missing\" does not resolve",
+				"/paths/~1a/post/requestBody/$ref: reference \"#/components/requestBodies/Missing\" does not resolve",
+				"/paths/~1a/post/responses/200/$ref: reference \"#/components/responses/Missing\" does not resolve",
+				"/paths/~1a/post/responses/400/content/application~1json/schema/$ref: reference \"#/components/schemas/Missing\" does not resolve",
+			},
+		},
+		{
+			"path parameters",
+			`openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /a/{id}:
+    parameters:
+      - {name: other, in: path, required: true, schema: {type: string}}
+    get:
+      parameters:
+        - {name: q, in: query}
+        - {name: q, in: query}
+      responses:
+        '200': {description: OK}`,
+			[]string{
+				"/paths/~1a~1{id}/parameters/0: path parameter \"other\" is not in the path",
+				"/paths/~1a~1{id}/get/parameters/1: query parameter \"q\" is declared twice",
+				"/paths/~1a~1{id}/get/parameters: path parameter \"id\" is not declared",
+			},
+		},
+		{
+			"path-level parameter overridden by the operation",
+			`openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /a/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200': {description: OK}`,
+			nil,
+		},
+		{
+			"type arrays in 3.0",
+			`openapi: 3.0.3
+info: {title: T, version: "1"}
+paths: {}
+components:
+  schemas:
+    A: {type: [string, "null"]}`,
+			[]string{"/components/schemas/A/type: type arrays need OpenAPI 3.1"},
+		},
+		{
+			"nullable and unknown types in 3.1",
+			`openapi: 3.1.0
+info: {title: T, version: "1"}
+paths: {}
+components:
+  schemas:
+    A: {type: string, nullable: true}
+    B: {type: [str, "null"]}`,
+			[]string{
+				"/components/schemas/A/nullable: nullable is not an OpenAPI 3.1 keyword; add \"null\" to the type instead",
+				"/components/schemas/B/type/0: \"str\" is not a schema type",
+			},
+		},
+		{
+			"operations",
+			`openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /a/:id:
+    get:
+      operationId: get
+      parameters:
+        - {name: id, in: path, required: true}
+      security:
+        - missing: []
+  /b:
+    get:
+      operationId: get
+      responses:
+        '600': {description: Odd}
+        '200': {}
+  /c: {}`,
+			[]string{
+				"/paths/~1a~1:id: path \"/a/:id\" uses :name segments",
+				"/paths/~1a~1:id/get/responses: at least one response is required",
+				"/paths/~1a~1:id/get/security/0/missing: security scheme \"missing\" is not defined in components",
+				"/paths/~1b/get/operationId: operation ID \"get\" is also used by /paths/~1a~1:id/get",
+				"/paths/~1b/get/responses/200/description: the response description is required",
+				"/paths/~1b/get/responses/600: response status \"600\" is not a status code",
+				"/paths/~1c: path \"/c\" has no operations",
+			},
+		},
+	}
+	for _, tt := range tests {
+		doc, err := ParseDocument([]byte(tt.doc))
+		if err != nil {
+			t.Fatalf("%s: ParseDocument() = %v", tt.name, err)
+		}
+		problems := ValidateDocument(doc)
+		if len(problems) != len(tt.want) {
+			t.Errorf("%s: ValidateDocument() = %+v, want %d problems", tt.name, problems, len(tt.want))
+			continue
+		}
+		for i, p := range problems {
+			if got := p.Pointer + ": " + p.Message; !strings.HasPrefix(got, tt.want[i]) {
+				t.Errorf("%s: problem %d = %q, want %q", tt.name, i, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestValidateGeneratedSpec(t *testing.T) {
+	spec := Generate([]types.RouteInfo{
+		{Method: "GET", Path: "/users/:id", OperationID: "getUser"},
+		{Method: "GET", Path: "/users", OperationID: "getUser"},
+	}, "http://localhost:3000", types.AppMeta{})
+	problems := Validate(spec)
+	var found bool
+	for _, p := range problems {
+		if strings.Contains(p.Message, "operation ID \"getUser\" is also used") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Validate() = %+v, want the duplicate operation ID reported", problems)
+	}
+}
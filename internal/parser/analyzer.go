@@ -1,7 +1,159 @@
 package parser
 
-import "github.com/yourusername/atomicdocs/internal/types"
+import (
+	"fmt"
+	"path"
+	"strings"
 
-func AnalyzeRoute(route types.RouteInfo, schemaFiles map[string]string) types.RouteInfo {
-	return route
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// ImportResolution records what happened to one import of a route.
+type ImportResolution struct {
+	Name    string `json:"name"`
+	From    string `json:"from"`
+	File    string `json:"file,omitempty"`
+	Library string `json:"library,omitempty"`
+	Parsed  bool   `json:"parsed"`
+}
+
+// Where the request body and responses of a route come from.
+const (
+	SourceRoute   = "route"   // documented by the route itself
+	SourceDefault = "default" // filled in with generic responses
+	SourceNone    = "none"    // not documented
+)
+
+// RouteAnalysis describes how a route was documented. RequestBody is only
+// set for methods that take a body. Warnings explain what could not be
+// resolved or parsed, and why.
+type RouteAnalysis struct {
+	Method      string             `json:"method"`
+	Path        string             `json:"path"`
+	Imports     []ImportResolution `json:"imports,omitempty"`
+	RequestBody string             `json:"requestBody,omitempty"`
+	Responses   string             `json:"responses"`
+	Warnings    []string           `json:"warnings,omitempty"`
+}
+
+func (a *RouteAnalysis) warn(format string, args ...interface{}) {
+	a.Warnings = append(a.Warnings, fmt.Sprintf(format, args...))
+}
+
+// AnalyzeRoute resolves the route's imports against the schema files sent
+// with the registration and tries to parse them. The route is returned
+// unchanged: what the imports hold is reported, not added to the docs.
+func AnalyzeRoute(route types.RouteInfo, schemaFiles map[string]string) (types.RouteInfo, RouteAnalysis) {
+	analysis := RouteAnalysis{Method: route.Method, Path: route.Path, Responses: SourceRoute}
+	if acceptsBody(route.Method) {
+		analysis.RequestBody = SourceNone
+		if route.RequestBody != nil {
+			analysis.RequestBody = SourceRoute
+		}
+	}
+
+	for _, imp := range route.Imports {
+		res := ImportResolution{Name: imp.Name, From: imp.From}
+
+		file, ok := resolveImport(imp.From, schemaFiles)
+		if !ok {
+			analysis.Imports = append(analysis.Imports, res)
+			analysis.warn("import %s from %q matches none of the schema files sent", imp.Name, imp.From)
+			continue
+		}
+		res.File = file
+
+		properties, library := ParseSchema(imp.Name, schemaFiles[file])
+		res.Library = library
+		res.Parsed = properties != nil
+		if !res.Parsed {
+			analysis.warn("%s in %s could not be parsed (library: %s)", imp.Name, file, library)
+		}
+		analysis.Imports = append(analysis.Imports, res)
+	}
+
+	if route.Responses == nil {
+		analysis.Responses = SourceDefault
+	}
+	return route, analysis
+}
+
+// Summary counts what the analyses of a registration found. Libraries
+// counts the imports parsed by each schema library.
+type Summary struct {
+	Routes           int            `json:"routes"`
+	Imports          int            `json:"imports"`
+	Resolved         int            `json:"resolved"`
+	Parsed           int            `json:"parsed"`
+	Libraries        map[string]int `json:"libraries,omitempty"`
+	DefaultResponses int            `json:"defaultResponses"`
+	MissingBodies    int            `json:"missingBodies"`
+	Warnings         int            `json:"warnings"`
+}
+
+// Summarize counts the findings of the analyses of a registration.
+func Summarize(analyses []RouteAnalysis) Summary {
+	summary := Summary{Routes: len(analyses)}
+	for _, analysis := range analyses {
+		for _, imp := range analysis.Imports {
+			summary.Imports++
+			if imp.File != "" {
+				summary.Resolved++
+			}
+			if imp.Parsed {
+				summary.Parsed++
+				if summary.Libraries == nil {
+					summary.Libraries = map[string]int{}
+				}
+				summary.Libraries[imp.Library]++
+			}
+		}
+		if analysis.Responses == SourceDefault {
+			summary.DefaultResponses++
+		}
+		if analysis.RequestBody == SourceNone {
+			summary.MissingBodies++
+		}
+		summary.Warnings += len(analysis.Warnings)
+	}
+	return summary
+}
+
+func acceptsBody(method string) bool {
+	return method == "POST" || method == "PUT" || method == "PATCH"
+}
+
+// resolveImport finds the schema file an import specifier points at. Both
+// sides are compared without extensions and leading relative segments, so
+// "../schemas/user.schema" matches "src/schemas/user.schema.ts".
+func resolveImport(from string, schemaFiles map[string]string) (string, bool) {
+	want := normalizeModulePath(from)
+	if want == "" {
+		return "", false
+	}
+	for file := range schemaFiles {
+		have := normalizeModulePath(file)
+		if have == want || strings.HasSuffix(have, "/"+want) {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+func normalizeModulePath(p string) string {
+	p = strings.ReplaceAll(p, "\\", "/")
+	for _, ext := range []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"} {
+		p = strings.TrimSuffix(p, ext)
+	}
+	p = strings.TrimSuffix(p, "/index")
+	p = path.Clean(p)
+	for strings.HasPrefix(p, "../") {
+		p = strings.TrimPrefix(p, "../")
+	}
+	p = strings.TrimPrefix(p, "./")
+	p = strings.TrimPrefix(p, "/")
+	if p == "." || p == ".." {
+		return ""
+	}
+	return p
 }
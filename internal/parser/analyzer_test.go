@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+var schemaFiles = map[string]string{
+	"src/schemas/user.ts":  `export const UserSchema = z.object({ email: z.string(), age: z.number() })`,
+	"src/schemas/order.ts": `export const OrderSchema = z.object(orderShape)`,
+}
+
+func TestAnalyzeRouteLeavesRouteUnchanged(t *testing.T) {
+	route := types.RouteInfo{
+		Method:  "POST",
+		Path:    "/users",
+		Imports: []types.Import{{Name: "UserSchema", From: "../schemas/user"}},
+	}
+	got, analysis := AnalyzeRoute(route, schemaFiles)
+	if !reflect.DeepEqual(got, route) {
+		t.Errorf("AnalyzeRoute() route = %+v, want it unchanged", got)
+	}
+	if analysis.RequestBody != SourceNone {
+		t.Errorf("RequestBody = %q, want %q", analysis.RequestBody, SourceNone)
+	}
+}
+
+func TestAnalyzeRoute(t *testing.T) {
+	body := &types.RequestBody{Content: map[string]types.MediaTypeObject{"application/json": {}}}
+	responses := map[string]types.Response{"200": {Description: "OK"}}
+
+	tests := []struct {
+		name  string
+		route types.RouteInfo
+		want  RouteAnalysis
+	}{
+		{
+			"parsed import",
+			types.RouteInfo{Method: "POST", Path: "/users", RequestBody: body, Responses: responses, Imports: []types.Import{{Name: "UserSchema", From: "./schemas/user.js"}}},
+			RouteAnalysis{
+				Method: "POST", Path: "/users", RequestBody: SourceRoute, Responses: SourceRoute,
+				Imports: []ImportResolution{{Name: "UserSchema", From: "./schemas/user.js", File: "src/schemas/user.ts", Library: LibraryZod, Parsed: true}},
+			},
+		},
+		{
+			"unparsable import",
+			types.RouteInfo{Method: "PUT", Path: "/orders", Imports: []types.Import{{Name: "OrderSchema", From: "../schemas/order"}}},
+			RouteAnalysis{
+				Method: "PUT", Path: "/orders", RequestBody: SourceNone, Responses: SourceDefault,
+				Imports:  []ImportResolution{{Name: "OrderSchema", From: "../schemas/order", File: "src/schemas/order.ts", Library: LibraryZod}},
+				Warnings: []string{"OrderSchema in src/schemas/order.ts could not be parsed (library: zod)"},
+			},
+		},
+		{
+			"unresolved import",
+			types.RouteInfo{Method: "GET", Path: "/things", Responses: responses, Imports: []types.Import{{Name: "Thing", From: "./thing"}}},
+			RouteAnalysis{
+				Method: "GET", Path: "/things", Responses: SourceRoute,
+				Imports:  []ImportResolution{{Name: "Thing", From: "./thing"}},
+				Warnings: []string{`import Thing from "./thing" matches none of the schema files sent`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		if _, got := AnalyzeRoute(tt.route, schemaFiles); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: AnalyzeRoute() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var analyses []RouteAnalysis
+	for _, route := range []types.RouteInfo{
+		{Method: "POST", Path: "/users", Imports: []types.Import{{Name: "UserSchema", From: "../schemas/user"}}},
+		{Method: "PUT", Path: "/orders", Imports: []types.Import{{Name: "OrderSchema", From: "../schemas/order"}, {Name: "Missing", From: "./missing"}}},
+	} {
+		_, analysis := AnalyzeRoute(route, schemaFiles)
+		analyses = append(analyses, analysis)
+	}
+	want := Summary{
+		Routes: 2, Imports: 3, Resolved: 2, Parsed: 1,
+		Libraries:        map[string]int{LibraryZod: 1},
+		DefaultResponses: 2, MissingBodies: 2, Warnings: 2,
+	}
+	if got := Summarize(analyses); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveImport(t *testing.T) {
+	files := map[string]string{"src/schemas/user.schema.ts": "", "lib/auth/index.js": ""}
+	tests := []struct {
+		from, want string
+	}{
+		{"../schemas/user.schema", "src/schemas/user.schema.ts"},
+		{"./schemas/user.schema.js", "src/schemas/user.schema.ts"},
+		{"../auth", "lib/auth/index.js"},
+		{"../user", ""},
+		{"..", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := resolveImport(tt.from, files); got != tt.want {
+			t.Errorf("resolveImport(%q) = %q, want %q", tt.from, got, tt.want)
+		}
+	}
+}
@@ -7,28 +7,64 @@ import (
 )
 
 func ParseSchemaFromFile(schemaName string, fileContent string) map[string]types.Schema {
+	schema, _ := ParseSchema(schemaName, fileContent)
+	return schema
+}
+
+// Schema libraries recognised by ParseSchema.
+const (
+	LibraryZod     = "zod"
+	LibraryYup     = "yup"
+	LibraryJoi     = "joi"
+	LibraryUnknown = "unknown"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// ParseSchema parses the named schema out of a file and reports which
+// library the file uses. The library is reported even when parsing fails,
+// so callers can tell which parser needs work.
+func ParseSchema(schemaName string, fileContent string) (map[string]types.Schema, string) {
+	library := detectLibrary(fileContent)
+	if !identifierPattern.MatchString(schemaName) {
+		return nil, library
+	}
+	
 	// Try Zod first
 	if schema := parseZodSchema(schemaName, fileContent); schema != nil {
-		return schema
+		return schema, LibraryZod
 	}
 	
 	// Try Yup
 	if schema := parseYupSchema(schemaName, fileContent); schema != nil {
-		return schema
+		return schema, LibraryYup
 	}
 	
 	// Try Joi
 	if schema := parseJoiSchema(schemaName, fileContent); schema != nil {
-		return schema
+		return schema, LibraryJoi
 	}
 	
-	return nil
+	return nil, library
+}
+
+func detectLibrary(content string) string {
+	switch {
+	case strings.Contains(content, "z.object("):
+		return LibraryZod
+	case strings.Contains(content, "yup.object("):
+		return LibraryYup
+	case strings.Contains(content, "Joi.object("):
+		return LibraryJoi
+	default:
+		return LibraryUnknown
+	}
 }
 
 func parseZodSchema(schemaName string, content string) map[string]types.Schema {
 	// Pattern: export const SignupSchema = z.object({ ... })
 	// Use (?s) for multiline matching
-	pattern := regexp.MustCompile(`(?s)(?:export\s+)?(?:const|let|var)\s+` + schemaName + `\s*=\s*z\.object\(\{(.+?)\}\)`)
+	pattern := regexp.MustCompile(`(?s)(?:export\s+)?(?:const|let|var)\s+` + regexp.QuoteMeta(schemaName) + `\s*=\s*z\.object\(\{(.+?)\}\)`)
 	matches := pattern.FindStringSubmatch(content)
 	
 	if len(matches) < 2 {
@@ -56,7 +92,7 @@ func parseZodSchema(schemaName string, content string) map[string]types.Schema {
 
 func parseYupSchema(schemaName string, content string) map[string]types.Schema {
 	// Pattern: export const SignupSchema = yup.object({ ... })
-	pattern := regexp.MustCompile(`(?:export\s+)?(?:const|let|var)\s+` + schemaName + `\s*=\s*yup\.object\(\{([^}]+)\}\)`)
+	pattern := regexp.MustCompile(`(?:export\s+)?(?:const|let|var)\s+` + regexp.QuoteMeta(schemaName) + `\s*=\s*yup\.object\(\{([^}]+)\}\)`)
 	matches := pattern.FindStringSubmatch(content)
 	
 	if len(matches) < 2 {
@@ -84,7 +120,7 @@ func parseYupSchema(schemaName string, content string) map[string]types.Schema {
 
 func parseJoiSchema(schemaName string, content string) map[string]types.Schema {
 	// Pattern: export const SignupSchema = Joi.object({ ... })
-	pattern := regexp.MustCompile(`(?:export\s+)?(?:const|let|var)\s+` + schemaName + `\s*=\s*Joi\.object\(\{([^}]+)\}\)`)
+	pattern := regexp.MustCompile(`(?:export\s+)?(?:const|let|var)\s+` + regexp.QuoteMeta(schemaName) + `\s*=\s*Joi\.object\(\{([^}]+)\}\)`)
 	matches := pattern.FindStringSubmatch(content)
 	
 	if len(matches) < 2 {
@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrPrivateAddress is returned when a target resolves to a loopback,
+// private or link-local address that is not allowed.
+var ErrPrivateAddress = errors.New("target resolves to a private address")
+
+const dialTimeout = 5 * time.Second
+
+// allowedHost reports whether host, a host name with an optional port, is
+// in Config.AllowHosts.
+func (p *Proxy) allowedHost(host string) bool {
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	name = strings.Trim(name, "[]")
+	for _, allowed := range p.config.AllowHosts {
+		if strings.EqualFold(allowed, host) || strings.EqualFold(strings.Trim(allowed, "[]"), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// dial connects to addr, refusing private addresses unless they are
+// allowed. The check happens on the resolved addresses, so a public name
+// pointing at an internal one is refused too.
+func (p *Proxy) dial(addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	allowAll := p.config.AllowPrivate || p.allowedHost(addr)
+	var dialer net.Dialer
+	var lastErr error
+	for _, ip := range ips {
+		if !allowAll && private(ip.IP) {
+			lastErr = fmt.Errorf("%w: %s is %s", ErrPrivateAddress, host, ip.IP)
+			continue
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no addresses for %s", host)
+	}
+	return nil, lastErr
+}
+
+// dialLoopback connects to addr only if it resolves to a loopback address.
+func dialLoopback(addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	lastErr := fmt.Errorf("%s does not resolve to a loopback address", host)
+	for _, ip := range ips {
+		if !ip.IP.IsLoopback() {
+			continue
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// private reports whether ip is not on the public internet.
+func private(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast()
+}
@@ -0,0 +1,243 @@
+// Package proxy forwards try-it requests from the docs pages to the
+// documented app, so they work whatever the app's CORS policy is.
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	pathpkg "path"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// TargetHeader names the base URL a proxied request is for, e.g.
+// "https://staging.example.com". It must be one of the app's servers;
+// without it the first one is used.
+const TargetHeader = "X-AtomicDocs-Target"
+
+// AuthorizationHeader carries the Authorization header of a try-it request.
+// The Authorization header the proxy receives holds the viewer's
+// credentials for the docs and is never forwarded.
+const AuthorizationHeader = "X-AtomicDocs-Authorization"
+
+// Config limits the proxy. Only the request headers in AllowHeaders, and
+// the headers an app documents as parameters or API keys, are forwarded.
+//
+// Apps register their servers themselves, so the proxy only trusts them
+// when registration requires credentials. Hosts in AllowHosts are trusted
+// either way. Loopback, private and link-local addresses are refused unless
+// their host is in AllowHosts or AllowPrivate is set, except for the app's
+// own port on this machine when its servers are trusted.
+type Config struct {
+	TimeoutSeconds   int      `json:"timeoutSeconds,omitempty"`
+	MaxRequestBytes  int      `json:"maxRequestBytes,omitempty"`
+	MaxResponseBytes int      `json:"maxResponseBytes,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`
+	// AllowHosts lists host names, or host:port pairs, the proxy may reach
+	// whatever apps register.
+	AllowHosts   []string `json:"allowHosts,omitempty"`
+	AllowPrivate bool     `json:"allowPrivate,omitempty"`
+}
+
+var (
+	// ErrTargetNotAllowed is returned for targets that are not a server
+	// the app registered.
+	ErrTargetNotAllowed = errors.New("target is not a registered server of the app")
+	// ErrUntrustedServers is returned for targets outside AllowHosts when
+	// anyone may register an app.
+	ErrUntrustedServers = errors.New("the proxy only reaches allowed hosts while registration is open")
+	// ErrRequestTooLarge is returned for bodies over MaxRequestBytes.
+	ErrRequestTooLarge = errors.New("request body is too large")
+)
+
+// hopHeaders apply to one connection and are never forwarded.
+var hopHeaders = map[string]bool{
+	"connection":          true,
+	"keep-alive":          true,
+	"proxy-authenticate":  true,
+	"proxy-authorization": true,
+	"te":                  true,
+	"trailer":             true,
+	"transfer-encoding":   true,
+	"upgrade":             true,
+}
+
+// Proxy forwards requests to registered apps.
+type Proxy struct {
+	config Config
+	client *fasthttp.Client
+	// loopback reaches apps on this machine; see AppTarget.
+	loopback *fasthttp.Client
+}
+
+func New(cfg Config) *Proxy {
+	p := &Proxy{config: cfg}
+	p.client = &fasthttp.Client{
+		Name:                "atomicdocs-proxy",
+		MaxResponseBodySize: cfg.MaxResponseBytes,
+		Dial:                p.dial,
+	}
+	p.loopback = &fasthttp.Client{
+		Name:                "atomicdocs-proxy",
+		MaxResponseBodySize: cfg.MaxResponseBytes,
+		Dial:                dialLoopback,
+	}
+	return p
+}
+
+// Enabled reports whether the proxy can reach anything. trustServers tells
+// whether the servers apps register are trusted, which they are when
+// registration requires credentials.
+func (p *Proxy) Enabled(trustServers bool) bool {
+	return trustServers || len(p.config.AllowHosts) > 0
+}
+
+// Check returns nil when the proxy may send requests to target, a URL
+// Target returned.
+func (p *Proxy) Check(target string, trustServers bool) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ErrTargetNotAllowed
+	}
+	if !trustServers && !p.allowedHost(u.Host) {
+		return ErrUntrustedServers
+	}
+	return nil
+}
+
+// AppTarget reports whether target, a URL Target returned, is the app
+// itself on this machine: a loopback host on the port the app registered
+// with, as in the default server http://localhost:<app>.
+func AppTarget(target, app string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	host := strings.ToLower(u.Hostname())
+	if port != app {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Target returns the URL a request for path goes to. target is the value
+// of TargetHeader; the resulting URL must lie under one of servers, the
+// app's base URLs.
+func Target(servers []string, target, path string) (string, error) {
+	if len(servers) == 0 {
+		return "", ErrTargetNotAllowed
+	}
+	if target == "" {
+		target = servers[0]
+	}
+	base, err := url.Parse(strings.TrimSuffix(target, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" || base.RawQuery != "" || base.User != nil {
+		return "", ErrTargetNotAllowed
+	}
+
+	// Resolve dot segments here: the client would otherwise resolve them
+	// after the prefix check below, climbing out of the server's base path.
+	joined := pathpkg.Clean(base.Path + "/" + path)
+	if strings.HasSuffix(path, "/") && joined != "/" {
+		joined += "/"
+	}
+	var escaped []string
+	for _, segment := range strings.Split(strings.TrimPrefix(joined, "/"), "/") {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	full := strings.ToLower(base.Scheme+"://"+base.Host) + "/" + strings.Join(escaped, "/")
+
+	for _, server := range servers {
+		server = strings.TrimSuffix(server, "/")
+		scheme, rest, ok := strings.Cut(server, "://")
+		if !ok {
+			continue
+		}
+		host, serverPath, _ := strings.Cut(rest, "/")
+		prefix := strings.ToLower(scheme+"://"+host) + strings.TrimSuffix("/"+serverPath, "/")
+		if full == prefix || strings.HasPrefix(full, prefix+"/") {
+			return full, nil
+		}
+	}
+	return "", ErrTargetNotAllowed
+}
+
+// Forward sends the request to target, which Target returned, and writes
+// the app's response to ctx. Besides Config.AllowHeaders, the request
+// headers in extraHeaders are forwarded, and AuthorizationHeader is sent
+// as Authorization. The time the app took is
+// returned and reported in a Server-Timing header.
+//
+// loopback lets the request reach loopback addresses, and only those; set
+// it for targets AppTarget accepts.
+func (p *Proxy) Forward(ctx *fasthttp.RequestCtx, target string, extraHeaders []string, loopback bool) (time.Duration, error) {
+	body := ctx.PostBody()
+	if p.config.MaxRequestBytes > 0 && len(body) > p.config.MaxRequestBytes {
+		return 0, ErrRequestTooLarge
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	uri := target
+	if query := ctx.URI().QueryString(); len(query) > 0 {
+		uri += "?" + string(query)
+	}
+	req.SetRequestURI(uri)
+	req.Header.SetMethodBytes(ctx.Method())
+	for _, names := range [][]string{p.config.AllowHeaders, extraHeaders} {
+		for _, name := range names {
+			lower := strings.ToLower(name)
+			if value := ctx.Request.Header.Peek(name); len(value) > 0 && !hopHeaders[lower] && lower != "authorization" {
+				req.Header.SetBytesV(name, value)
+			}
+		}
+	}
+	if value := ctx.Request.Header.Peek(AuthorizationHeader); len(value) > 0 {
+		req.Header.SetBytesV("Authorization", value)
+	}
+	req.SetBody(body)
+
+	timeout := time.Duration(p.config.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	start := time.Now()
+	client := p.client
+	if loopback {
+		client = p.loopback
+	}
+	err := client.DoTimeout(req, resp, timeout)
+	elapsed := time.Since(start)
+	if err != nil {
+		return elapsed, err
+	}
+
+	ctx.SetStatusCode(resp.StatusCode())
+	resp.Header.VisitAll(func(key, value []byte) {
+		name := strings.ToLower(string(key))
+		// Cookies would be set on the docs server's origin, and the app's
+		// CORS policy does not apply to the proxy's.
+		if hopHeaders[name] || name == "set-cookie" || name == "content-length" || strings.HasPrefix(name, "access-control-") {
+			return
+		}
+		ctx.Response.Header.SetBytesKV(key, value)
+	})
+	ctx.Response.Header.Set("Server-Timing", fmt.Sprintf("app;dur=%.1f", float64(elapsed.Microseconds())/1000))
+	ctx.SetBody(resp.Body())
+	return elapsed, nil
+}
@@ -0,0 +1,191 @@
+package proxy
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestTarget(t *testing.T) {
+	servers := []string{"http://localhost:3000", "https://api.example.com/v1/"}
+
+	tests := []struct {
+		name   string
+		target string
+		path   string
+		want   string
+		err    error
+	}{
+		{"default server", "", "/users/1", "http://localhost:3000/users/1", nil},
+		{"named server", "https://api.example.com/v1", "/users", "https://api.example.com/v1/users", nil},
+		{"host case", "HTTP://LOCALHOST:3000", "/users", "http://localhost:3000/users", nil},
+		{"trailing slash kept", "", "/users/", "http://localhost:3000/users/", nil},
+		{"escaped segment", "", "/files/a b", "http://localhost:3000/files/a%20b", nil},
+		{"dot segments resolved", "https://api.example.com/v1", "/users/../orders", "https://api.example.com/v1/orders", nil},
+		{"climbing out of base path", "https://api.example.com/v1", "/../admin", "", ErrTargetNotAllowed},
+		{"climbing with a prefix", "https://api.example.com/v1", "/users/../../admin", "", ErrTargetNotAllowed},
+		{"climbing via target", "https://api.example.com/v1/..", "/admin", "", ErrTargetNotAllowed},
+		{"unregistered server", "http://169.254.169.254", "/latest", "", ErrTargetNotAllowed},
+		{"other port", "http://localhost:6379", "/", "", ErrTargetNotAllowed},
+		{"user info", "http://user@localhost:3000", "/", "", ErrTargetNotAllowed},
+		{"other scheme", "file://localhost:3000", "/", "", ErrTargetNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Target(servers, tt.target, tt.path)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Errorf("Target() = %q, %v, want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	open := New(Config{})
+	if open.Enabled(false) {
+		t.Error("proxy without allowed hosts is enabled while registration is open")
+	}
+	if err := open.Check("http://localhost:3000/users", false); err != ErrUntrustedServers {
+		t.Errorf("Check() with open registration = %v, want %v", err, ErrUntrustedServers)
+	}
+	if err := open.Check("http://localhost:3000/users", true); err != nil {
+		t.Errorf("Check() with registration auth = %v, want nil", err)
+	}
+
+	allowed := New(Config{AllowHosts: []string{"localhost", "api.example.com:8443"}})
+	tests := []struct {
+		target string
+		want   error
+	}{
+		{"http://localhost:3000/users", nil},
+		{"http://LOCALHOST/users", nil},
+		{"https://api.example.com:8443/users", nil},
+		{"https://api.example.com/users", ErrUntrustedServers},
+		{"http://127.0.0.1:3000/users", ErrUntrustedServers},
+	}
+	for _, tt := range tests {
+		if err := allowed.Check(tt.target, false); err != tt.want {
+			t.Errorf("Check(%q) = %v, want %v", tt.target, err, tt.want)
+		}
+	}
+}
+
+func TestPrivate(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"0.0.0.0", true},
+		{"::ffff:127.0.0.1", true},
+		{"93.184.216.34", false},
+		{"2606:4700::1111", false},
+	}
+	for _, tt := range tests {
+		if got := private(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("private(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestDialRefusesPrivateAddresses(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	addr := ln.Addr().String()
+
+	if _, err := New(Config{}).dial(addr); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("dial() = %v, want %v", err, ErrPrivateAddress)
+	}
+	for _, cfg := range []Config{{AllowPrivate: true}, {AllowHosts: []string{"127.0.0.1"}}} {
+		conn, err := New(cfg).dial(addr)
+		if err != nil {
+			t.Errorf("dial() with %+v = %v, want a connection", cfg, err)
+			continue
+		}
+		conn.Close()
+	}
+}
+
+func TestAppTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"http://localhost:3000/users", true},
+		{"http://LOCALHOST:3000/", true},
+		{"http://127.0.0.1:3000/users", true},
+		{"http://[::1]:3000/users", true},
+		{"http://localhost:3001/users", false},
+		{"http://10.0.0.1:3000/users", false},
+		{"https://api.example.com:3000/users", false},
+		{"http://localhost/users", false},
+	}
+	for _, tt := range tests {
+		if got := AppTarget(tt.target, "3000"); got != tt.want {
+			t.Errorf("AppTarget(%q, 3000) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestForwardToLoopbackApp checks that an app on this machine is reached
+// through its default server only when Forward is allowed loopback.
+func TestForwardToLoopbackApp(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyString("hello from " + string(ctx.Path()))
+	})
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	target, err := Target([]string{"http://localhost:" + port}, "", "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !AppTarget(target, port) {
+		t.Fatalf("AppTarget(%q, %s) = false", target, port)
+	}
+
+	p := New(Config{})
+	var refused fasthttp.RequestCtx
+	if _, err := p.Forward(&refused, target, nil, false); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("Forward() without loopback = %v, want %v", err, ErrPrivateAddress)
+	}
+
+	var ctx fasthttp.RequestCtx
+	if _, err := p.Forward(&ctx, target, nil, true); err != nil {
+		t.Fatalf("Forward() with loopback = %v", err)
+	}
+	if got := string(ctx.Response.Body()); got != "hello from /users" {
+		t.Errorf("Forward() body = %q", got)
+	}
+}
+
+func TestDialLoopbackRefusesOtherAddresses(t *testing.T) {
+	if _, err := dialLoopback("192.0.2.1:80"); err == nil {
+		t.Error("dialLoopback() reached a non-loopback address")
+	}
+}
@@ -1,31 +1,218 @@
 package registry
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
 	"strconv"
 	"sync"
+	"time"
 	"github.com/yourusername/atomicdocs/internal/types"
 )
 
+// DefaultHistoryLimit is the number of snapshots kept per app when the
+// configuration does not set one.
+const DefaultHistoryLimit = 20
+
 type Registry struct {
-	mu    sync.RWMutex
-	apps  map[string][]types.RouteInfo
+	mu           sync.RWMutex
+	apps         map[string][]Snapshot
+	historyLimit int
+
+	subMu       sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// Version describes one stored registration of an app.
+type Version struct {
+	Number     int       `json:"version"`
+	Hash       string    `json:"hash"`
+	Timestamp  time.Time `json:"timestamp"`
+	GitSHA     string    `json:"gitSha,omitempty"`
+	RouteCount int       `json:"routes"`
+}
+
+// Snapshot is a registration as it was stored, routes and metadata included.
+type Snapshot struct {
+	Version
+	Routes []types.RouteInfo
+	Meta   types.AppMeta
+}
+
+// New creates a registry that keeps up to historyLimit snapshots per app.
+// A limit of zero or less uses DefaultHistoryLimit.
+func New(historyLimit int) *Registry {
+	if historyLimit <= 0 {
+		historyLimit = DefaultHistoryLimit
+	}
+	return &Registry{
+		apps:         make(map[string][]Snapshot),
+		historyLimit: historyLimit,
+		subscribers:  make(map[*subscriber]struct{}),
+	}
 }
 
-func New() *Registry {
-	return &Registry{apps: make(map[string][]types.RouteInfo)}
+// RegisterApp stores a new snapshot for the app unless its content is
+// identical to the latest one, and returns the version now current.
+// Subscribers are told about every new snapshot.
+func (r *Registry) RegisterApp(port int, routes []types.RouteInfo, meta types.AppMeta, gitSHA string) Version {
+	key := strconv.Itoa(port)
+	version, changed := r.store(key, routes, meta, gitSHA)
+	if changed {
+		r.publish(Event{App: key, Version: version})
+	}
+	return version
 }
 
-func (r *Registry) RegisterApp(port int, routes []types.RouteInfo) {
+func (r *Registry) store(key string, routes []types.RouteInfo, meta types.AppMeta, gitSHA string) (Version, bool) {
+	hash := contentHash(routes, meta)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.apps[strconv.Itoa(port)] = routes
+	history := r.apps[key]
+
+	next := 1
+	if n := len(history); n > 0 {
+		latest := history[n-1]
+		if latest.Hash == hash {
+			return latest.Version, false
+		}
+		next = latest.Number + 1
+	}
+
+	snap := Snapshot{
+		Version: Version{
+			Number:     next,
+			Hash:       hash,
+			Timestamp:  time.Now().UTC(),
+			GitSHA:     gitSHA,
+			RouteCount: len(routes),
+		},
+		Routes: routes,
+		Meta:   meta,
+	}
+	history = append(history, snap)
+	if len(history) > r.historyLimit {
+		history = append([]Snapshot(nil), history[len(history)-r.historyLimit:]...)
+	}
+	r.apps[key] = history
+	return snap.Version, true
+}
+
+// Registered reports whether an app has registered at least once.
+func (r *Registry) Registered(port string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.apps[port]) > 0
 }
 
 func (r *Registry) GetByPort(port string) []types.RouteInfo {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if routes, ok := r.apps[port]; ok {
-		return routes
+	if history := r.apps[port]; len(history) > 0 {
+		return history[len(history)-1].Routes
 	}
 	return []types.RouteInfo{}
 }
+
+func (r *Registry) GetMeta(port string) types.AppMeta {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if history := r.apps[port]; len(history) > 0 {
+		return history[len(history)-1].Meta
+	}
+	return types.AppMeta{}
+}
+
+// GetPrevious returns the registration an app replaced with its latest one.
+func (r *Registry) GetPrevious(port string) ([]types.RouteInfo, types.AppMeta, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	history := r.apps[port]
+	if len(history) < 2 {
+		return nil, types.AppMeta{}, false
+	}
+	prev := history[len(history)-2]
+	return prev.Routes, prev.Meta, true
+}
+
+// ErrNotResponding is returned by Ping when the registry stays locked.
+var ErrNotResponding = errors.New("registry is not responding")
+
+// Ping checks that the registry can be read within timeout, failing when a
+// writer holds its lock for longer.
+func (r *Registry) Ping(timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		r.mu.RLock()
+		r.mu.RUnlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return ErrNotResponding
+	}
+}
+
+// AppSummary describes a registered app by its current version.
+type AppSummary struct {
+	App     string  `json:"app"`
+	Current Version `json:"current"`
+	Stored  int     `json:"versions"`
+}
+
+// Apps lists the registered apps sorted by key.
+func (r *Registry) Apps() []AppSummary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	apps := make([]AppSummary, 0, len(r.apps))
+	for key, history := range r.apps {
+		if len(history) == 0 {
+			continue
+		}
+		apps = append(apps, AppSummary{
+			App:     key,
+			Current: history[len(history)-1].Version,
+			Stored:  len(history),
+		})
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].App < apps[j].App })
+	return apps
+}
+
+// History lists the stored versions of an app, oldest first.
+func (r *Registry) History(port string) []Version {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := make([]Version, 0, len(r.apps[port]))
+	for _, snap := range r.apps[port] {
+		versions = append(versions, snap.Version)
+	}
+	return versions
+}
+
+// GetVersion returns a stored snapshot. Versions that fell out of the
+// history window are reported as missing.
+func (r *Registry) GetVersion(port string, version int) (Snapshot, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, snap := range r.apps[port] {
+		if snap.Number == version {
+			return snap, true
+		}
+	}
+	return Snapshot{}, false
+}
+
+func contentHash(routes []types.RouteInfo, meta types.AppMeta) string {
+	data, _ := json.Marshal(struct {
+		Routes []types.RouteInfo `json:"routes"`
+		Meta   types.AppMeta     `json:"meta"`
+	}{routes, meta})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
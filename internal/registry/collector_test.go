@@ -0,0 +1,20 @@
+package registry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	r := New(DefaultHistoryLimit)
+	if err := r.Ping(time.Second); err != nil {
+		t.Fatalf("Ping() = %v, want nil", err)
+	}
+
+	r.mu.Lock()
+	err := r.Ping(10 * time.Millisecond)
+	r.mu.Unlock()
+	if err != ErrNotResponding {
+		t.Errorf("Ping() while locked = %v, want %v", err, ErrNotResponding)
+	}
+}
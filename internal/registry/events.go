@@ -0,0 +1,54 @@
+package registry
+
+// Event announces that an app registered a new version.
+type Event struct {
+	App string `json:"app"`
+	Version
+}
+
+type subscriber struct {
+	app    string
+	events chan Event
+}
+
+// Subscribe returns a channel receiving the events of one app, or of every
+// app when app is empty, and a function that ends the subscription. A
+// subscriber that falls behind only gets the most recent event, which is
+// all a docs page needs to refresh.
+func (r *Registry) Subscribe(app string) (<-chan Event, func()) {
+	sub := &subscriber{app: app, events: make(chan Event, 1)}
+
+	r.subMu.Lock()
+	r.subscribers[sub] = struct{}{}
+	r.subMu.Unlock()
+
+	cancel := func() {
+		r.subMu.Lock()
+		defer r.subMu.Unlock()
+		if _, ok := r.subscribers[sub]; ok {
+			delete(r.subscribers, sub)
+			close(sub.events)
+		}
+	}
+	return sub.events, cancel
+}
+
+func (r *Registry) publish(event Event) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	for sub := range r.subscribers {
+		if sub.app != "" && sub.app != event.App {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Replace the event the subscriber has not read yet.
+			select {
+			case <-sub.events:
+			default:
+			}
+			sub.events <- event
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package router
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Middleware wraps a handler. Middleware registered with Use runs for every
+// request, including ones that end in 404 or 405.
+type Middleware func(fasthttp.RequestHandler) fasthttp.RequestHandler
+
+// Router dispatches requests by method and path pattern. Patterns are made
+// of "/"-separated segments: literals, "{name}" parameters matching one
+// segment, and a trailing "{name...}" matching the rest of the path.
+// When several patterns match, the one with more literal segments wins.
+type Router struct {
+	routes     []*route
+	middleware []Middleware
+
+	NotFound         fasthttp.RequestHandler
+	MethodNotAllowed fasthttp.RequestHandler
+}
+
+const patternKey = "atomicdocs.route"
+
+type route struct {
+	method   string
+	pattern  string
+	segments []segment
+	handler  fasthttp.RequestHandler
+	literals int
+}
+
+type segment struct {
+	literal string
+	param   string
+	rest    bool
+}
+
+func New() *Router {
+	return &Router{
+		NotFound: func(ctx *fasthttp.RequestCtx) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			ctx.SetContentType("application/json")
+			ctx.SetBodyString(`{"error":"Not found"}`)
+		},
+		MethodNotAllowed: func(ctx *fasthttp.RequestCtx) {
+			ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+			ctx.SetContentType("application/json")
+			ctx.SetBodyString(`{"error":"Method not allowed"}`)
+		},
+	}
+}
+
+func (r *Router) Use(mw ...Middleware) {
+	r.middleware = append(r.middleware, mw...)
+}
+
+// Handle registers a handler for method and pattern, wrapped in the given
+// route-specific middleware.
+func (r *Router) Handle(method, pattern string, handler fasthttp.RequestHandler, mw ...Middleware) {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	rt := &route{method: method, pattern: pattern, handler: handler}
+	for _, part := range split(pattern) {
+		switch {
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "...}"):
+			rt.segments = append(rt.segments, segment{param: part[1 : len(part)-4], rest: true})
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			rt.segments = append(rt.segments, segment{param: part[1 : len(part)-1]})
+		default:
+			rt.segments = append(rt.segments, segment{literal: part})
+			rt.literals++
+		}
+	}
+	r.routes = append(r.routes, rt)
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return r.routes[i].literals > r.routes[j].literals
+	})
+}
+
+func (r *Router) GET(pattern string, handler fasthttp.RequestHandler, mw ...Middleware) {
+	r.Handle(fasthttp.MethodGet, pattern, handler, mw...)
+}
+
+func (r *Router) POST(pattern string, handler fasthttp.RequestHandler, mw ...Middleware) {
+	r.Handle(fasthttp.MethodPost, pattern, handler, mw...)
+}
+
+// Group returns a router view that prefixes patterns and adds middleware to
+// every route registered through it.
+func (r *Router) Group(prefix string, mw ...Middleware) *Group {
+	return &Group{router: r, prefix: strings.TrimSuffix(prefix, "/"), middleware: mw}
+}
+
+// Handler returns the request handler with the global middleware applied.
+func (r *Router) Handler() fasthttp.RequestHandler {
+	var handler fasthttp.RequestHandler = r.dispatch
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler
+}
+
+func (r *Router) dispatch(ctx *fasthttp.RequestCtx) {
+	method := string(ctx.Method())
+	parts := split(string(ctx.Path()))
+
+	var allowed []string
+	for _, rt := range r.routes {
+		params, ok := rt.match(parts)
+		if !ok {
+			continue
+		}
+		if rt.method != method && !(method == fasthttp.MethodHead && rt.method == fasthttp.MethodGet) {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		for name, value := range params {
+			ctx.SetUserValue(name, value)
+		}
+		ctx.SetUserValue(patternKey, rt.pattern)
+		rt.handler(ctx)
+		return
+	}
+
+	if len(allowed) > 0 {
+		ctx.Response.Header.Set("Allow", strings.Join(allowed, ", "))
+		r.MethodNotAllowed(ctx)
+		return
+	}
+	r.NotFound(ctx)
+}
+
+func (rt *route) match(parts []string) (map[string]string, bool) {
+	var params map[string]string
+	for i, seg := range rt.segments {
+		if seg.rest {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[seg.param] = strings.Join(parts[i:], "/")
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		if seg.param == "" {
+			if seg.literal != parts[i] {
+				return nil, false
+			}
+			continue
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[seg.param] = parts[i]
+	}
+	return params, len(parts) == len(rt.segments)
+}
+
+// Param returns a path parameter of the matched route.
+func Param(ctx *fasthttp.RequestCtx, name string) string {
+	if value, ok := ctx.UserValue(name).(string); ok {
+		return value
+	}
+	return ""
+}
+
+// Pattern returns the pattern of the route that handled the request, or ""
+// when no route matched. Global middleware can read it after calling the
+// next handler.
+func Pattern(ctx *fasthttp.RequestCtx) string {
+	pattern, _ := ctx.UserValue(patternKey).(string)
+	return pattern
+}
+
+func split(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []Middleware
+}
+
+func (g *Group) Handle(method, pattern string, handler fasthttp.RequestHandler, mw ...Middleware) {
+	all := append(append([]Middleware(nil), g.middleware...), mw...)
+	g.router.Handle(method, g.prefix+pattern, handler, all...)
+}
+
+func (g *Group) GET(pattern string, handler fasthttp.RequestHandler, mw ...Middleware) {
+	g.Handle(fasthttp.MethodGet, pattern, handler, mw...)
+}
+
+func (g *Group) POST(pattern string, handler fasthttp.RequestHandler, mw ...Middleware) {
+	g.Handle(fasthttp.MethodPost, pattern, handler, mw...)
+}
+
+func (g *Group) Group(prefix string, mw ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     g.prefix + strings.TrimSuffix(prefix, "/"),
+		middleware: append(append([]Middleware(nil), g.middleware...), mw...),
+	}
+}
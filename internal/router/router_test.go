@@ -0,0 +1,179 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serve runs one request through the router and returns the response.
+func serve(r *Router, method, path string) *fasthttp.Response {
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(path)
+	r.Handler()(&ctx)
+	return &ctx.Response
+}
+
+// echo answers with the route's name and the named parameters.
+func echo(name string, params ...string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		values := []string{name}
+		for _, param := range params {
+			values = append(values, param+"="+Param(ctx, param))
+		}
+		ctx.SetBodyString(strings.Join(values, " "))
+	}
+}
+
+func TestMatch(t *testing.T) {
+	r := New()
+	r.GET("/", echo("root"))
+	r.GET("/apps", echo("apps"))
+	r.GET("/apps/{port}", echo("app", "port"))
+	r.GET("/apps/{port}/versions/{version}", echo("version", "port", "version"))
+	r.GET("/apps/{port}/spec", echo("spec", "port"))
+	r.GET("/files/{path...}", echo("files", "path"))
+	r.POST("/apps", echo("create"))
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"GET", "/", 200, "root"},
+		{"GET", "/apps", 200, "apps"},
+		{"GET", "/apps/", 200, "apps"},
+		{"GET", "/apps/3000", 200, "app port=3000"},
+		{"GET", "/apps/3000/versions/7", 200, "version port=3000 version=7"},
+		{"GET", "/apps/3000/spec", 200, "spec port=3000"},
+		{"GET", "/files/a/b/c.json", 200, "files path=a/b/c.json"},
+		{"GET", "/files", 200, "files path="},
+		{"POST", "/apps", 200, "create"},
+		{"HEAD", "/apps/3000", 200, ""},
+		{"GET", "/apps/3000/versions", 404, ""},
+		{"GET", "/nope", 404, ""},
+		{"DELETE", "/apps", 405, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp := serve(r, tt.method, tt.path)
+			if resp.StatusCode() != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode(), tt.status)
+			}
+			if tt.status == 200 && tt.method != "HEAD" && string(resp.Body()) != tt.body {
+				t.Errorf("body = %q, want %q", resp.Body(), tt.body)
+			}
+		})
+	}
+}
+
+func TestLiteralsWin(t *testing.T) {
+	r := New()
+	// Registered first, the parameter route would match /apps/latest too.
+	r.GET("/apps/{port}", echo("app", "port"))
+	r.GET("/apps/latest", echo("latest"))
+
+	if body := string(serve(r, "GET", "/apps/latest").Body()); body != "latest" {
+		t.Errorf("body = %q, want %q", body, "latest")
+	}
+	if body := string(serve(r, "GET", "/apps/3000").Body()); body != "app port=3000" {
+		t.Errorf("body = %q, want %q", body, "app port=3000")
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	r := New()
+	r.GET("/items", echo("list"))
+	r.POST("/items", echo("create"))
+
+	resp := serve(r, "PUT", "/items")
+	if resp.StatusCode() != fasthttp.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", resp.StatusCode())
+	}
+	if allow := string(resp.Header.Peek("Allow")); allow != "GET, POST" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, POST")
+	}
+}
+
+// tag is middleware that appends name to the X-Trace response header.
+func tag(name string) Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			trace := string(ctx.Response.Header.Peek("X-Trace"))
+			ctx.Response.Header.Set("X-Trace", strings.TrimPrefix(trace+","+name, ","))
+			next(ctx)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	r := New()
+	r.Use(tag("global"))
+	docs := r.Group("/docs/", tag("docs"))
+	docs.GET("", echo("index"))
+	docs.GET("/json", echo("json"), tag("route"))
+	api := docs.Group("/api", tag("api"))
+	api.GET("/{port}", echo("api", "port"))
+	r.GET("/health", echo("health"))
+
+	tests := []struct {
+		path  string
+		body  string
+		trace string
+	}{
+		{"/docs", "index", "global,docs"},
+		{"/docs/json", "json", "global,docs,route"},
+		{"/docs/api/3000", "api port=3000", "global,docs,api"},
+		{"/health", "health", "global"},
+		{"/missing", "", "global"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp := serve(r, "GET", tt.path)
+			if tt.body != "" && string(resp.Body()) != tt.body {
+				t.Errorf("body = %q, want %q", resp.Body(), tt.body)
+			}
+			if trace := string(resp.Header.Peek("X-Trace")); trace != tt.trace {
+				t.Errorf("X-Trace = %q, want %q", trace, tt.trace)
+			}
+		})
+	}
+}
+
+func TestGroupsDoNotShareMiddleware(t *testing.T) {
+	r := New()
+	parent := r.Group("/a", tag("a"))
+	first := parent.Group("/b", tag("b"))
+	second := parent.Group("/c", tag("c"))
+	first.GET("", echo("b"))
+	second.GET("", echo("c"))
+
+	for path, want := range map[string]string{"/a/b": "a,b", "/a/c": "a,c"} {
+		if trace := string(serve(r, "GET", path).Header.Peek("X-Trace")); trace != want {
+			t.Errorf("%s: X-Trace = %q, want %q", path, trace, want)
+		}
+	}
+}
+
+func TestPattern(t *testing.T) {
+	r := New()
+	var seen []string
+	r.Use(func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			next(ctx)
+			seen = append(seen, fmt.Sprintf("%d %s", ctx.Response.StatusCode(), Pattern(ctx)))
+		}
+	})
+	r.GET("/apps/{port}", echo("app"))
+
+	serve(r, "GET", "/apps/3000")
+	serve(r, "GET", "/other")
+	want := []string{"200 /apps/{port}", "404 "}
+	if strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Errorf("patterns = %q, want %q", seen, want)
+	}
+}
@@ -0,0 +1,494 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/internal/export"
+	"github.com/yourusername/atomicdocs/internal/mock"
+	"github.com/yourusername/atomicdocs/internal/openapi"
+	"github.com/yourusername/atomicdocs/internal/types"
+)
+
+// Site is what the templates render: the spec arranged into pages.
+type Site struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []types.Server
+	Tags        []*Tag
+	Operations  []*Operation
+	Schemas     []*SchemaPage
+	Security    []Security
+}
+
+// Tag groups the operations listed under it. Operations without tags are
+// grouped under "default".
+type Tag struct {
+	Name        string
+	Slug        string
+	Description string
+	Operations  []*Operation
+}
+
+type Operation struct {
+	Slug        string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tags        []string
+	Parameters  []Parameter
+	RequestBody *Body
+	Responses   []Response
+	// Security lists the alternative sets of schemes that authorize the
+	// operation, e.g. "bearerAuth"; "none" allows anonymous calls.
+	Security []string
+	Curl     string
+}
+
+// Title is the summary, or the method and path without one.
+func (o *Operation) Title() string {
+	if o.Summary != "" {
+		return o.Summary
+	}
+	return o.Method + " " + o.Path
+}
+
+type Parameter struct {
+	Name        string
+	In          string
+	Required    bool
+	Type        string
+	Description string
+	Example     string
+}
+
+type Body struct {
+	Required    bool
+	Description string
+	Media       []Media
+}
+
+type Response struct {
+	Status      string
+	Description string
+	Headers     []Parameter
+	Media       []Media
+}
+
+// Media is the schema and an example of one content type.
+type Media struct {
+	ContentType string
+	Schema      *Schema
+	Example     string
+}
+
+// Schema is a schema laid out for display. Referenced schemas are not
+// expanded but linked to their page through Ref.
+type Schema struct {
+	Name        string
+	Type        string
+	Ref         string
+	Required    bool
+	Nullable    bool
+	Constraints []string
+	Enum        []string
+	Example     string
+	Properties  []*Schema
+	Items       *Schema
+}
+
+// SchemaPage is a component schema with a page of its own.
+type SchemaPage struct {
+	Name   string
+	Slug   string
+	Schema *Schema
+}
+
+type Security struct {
+	Name        string
+	Description string
+	Kind        string
+}
+
+// SearchEntry is one result the search box can find.
+type SearchEntry struct {
+	Title string `json:"title"`
+	Kind  string `json:"kind"`
+	Text  string `json:"text"`
+	URL   string `json:"url"`
+}
+
+// New arranges a spec into pages.
+func New(spec *openapi.Spec) *Site {
+	s := &Site{
+		Title:       spec.Info.Title,
+		Version:     spec.Info.Version,
+		Description: spec.Info.Description,
+		Servers:     spec.Servers,
+	}
+	if s.Title == "" {
+		s.Title = "API"
+	}
+
+	tags := map[string]*Tag{}
+	for _, tag := range spec.Tags {
+		if tags[tag.Name] == nil {
+			tags[tag.Name] = &Tag{Name: tag.Name, Description: tag.Description}
+			s.Tags = append(s.Tags, tags[tag.Name])
+		}
+	}
+	var extra []*Tag
+
+	// Component schemas get their slugs first, so references to them can
+	// link the right page.
+	var schemaNames []string
+	refs := map[string]string{}
+	if spec.Components != nil {
+		for name := range spec.Components.Schemas {
+			schemaNames = append(schemaNames, name)
+		}
+		sort.Strings(schemaNames)
+		schemaSlugs := slugs{}
+		for _, name := range schemaNames {
+			refs[name] = schemaSlugs.unique(name)
+		}
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range openapi.Methods {
+			op := spec.Paths[path].Operation(method)
+			if op == nil {
+				continue
+			}
+			view := newOperation(spec, refs, method, path, op)
+			s.Operations = append(s.Operations, view)
+
+			names := op.Tags
+			if len(names) == 0 {
+				names = []string{"default"}
+			}
+			for _, name := range names {
+				if tags[name] == nil {
+					tags[name] = &Tag{Name: name}
+					extra = append(extra, tags[name])
+				}
+				tags[name].Operations = append(tags[name].Operations, view)
+			}
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i].Name < extra[j].Name })
+	s.Tags = append(s.Tags, extra...)
+
+	tagSlugs, opSlugs := slugs{}, slugs{}
+	for _, tag := range s.Tags {
+		tag.Slug = tagSlugs.unique(tag.Name)
+	}
+	for _, op := range s.Operations {
+		op.Slug = opSlugs.unique(op.Method + " " + op.Path)
+	}
+
+	if spec.Components != nil {
+		for _, name := range schemaNames {
+			s.Schemas = append(s.Schemas, &SchemaPage{Name: name, Slug: refs[name], Schema: newSchema(spec.Components.Schemas[name], refs, name, false)})
+		}
+
+		names := make([]string, 0, len(spec.Components.SecuritySchemes))
+		for name := range spec.Components.SecuritySchemes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			scheme := spec.Components.SecuritySchemes[name]
+			s.Security = append(s.Security, Security{Name: name, Description: scheme.Description, Kind: schemeKind(scheme)})
+		}
+	}
+	return s
+}
+
+func newOperation(spec *openapi.Spec, refs map[string]string, method, path string, op *openapi.Operation) *Operation {
+	view := &Operation{
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Curl:        export.Curl(spec, method, path),
+	}
+	key := method + " " + path
+
+	for _, param := range op.Parameters {
+		view.Parameters = append(view.Parameters, Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Required:    param.Required || param.In == "path",
+			Type:        typeLabel(param.Schema),
+			Description: param.Description,
+			Example:     exampleText(param.Example),
+		})
+	}
+
+	if op.RequestBody != nil {
+		view.RequestBody = &Body{
+			Required:    op.RequestBody.Required,
+			Description: op.RequestBody.Description,
+			Media:       newMedia(spec, refs, key+" body", op.RequestBody.Content),
+		}
+	}
+
+	statuses := make([]string, 0, len(op.Responses))
+	for status := range op.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		resp := op.Responses[status]
+		r := Response{Status: status, Description: resp.Description, Media: newMedia(spec, refs, key+" "+status, resp.Content)}
+		names := make([]string, 0, len(resp.Headers))
+		for name := range resp.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			header := resp.Headers[name]
+			r.Headers = append(r.Headers, Parameter{Name: name, In: "header", Type: typeLabel(header.Schema), Description: header.Description})
+		}
+		view.Responses = append(view.Responses, r)
+	}
+
+	for _, requirement := range op.Security {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			names = []string{"none"}
+		}
+		view.Security = append(view.Security, strings.Join(names, " + "))
+	}
+	return view
+}
+
+// newMedia lays out the content types of a body, with the documented
+// example or one synthesised as the mock server does. Request bodies are
+// keyed like the exports' example requests, so the example agrees with
+// the curl command. refs maps component schemas to the slugs of their
+// pages.
+func newMedia(spec *openapi.Spec, refs map[string]string, key string, content map[string]types.MediaTypeObject) []Media {
+	names := make([]string, 0, len(content))
+	for name := range content {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var media []Media
+	for _, name := range names {
+		m := content[name]
+		example := m.Example
+		if example == nil {
+			example = mock.NewGenerator(0, key, spec.ResolveSchema).Value(m.Schema, "")
+		}
+		text := ""
+		if s, ok := example.(string); ok && !strings.Contains(name, "json") {
+			text = s
+		} else if data, err := json.MarshalIndent(example, "", "  "); err == nil {
+			text = string(data)
+		}
+		// A body that is a reference is shown expanded, linking its name.
+		schema := m.Schema
+		root := newSchema(spec.ResolveSchema(schema), refs, "", false)
+		if schema.Ref != "" {
+			root.Ref = refSlug(refs, schema.Ref)
+			root.Type = refName(schema.Ref)
+		}
+		media = append(media, Media{ContentType: name, Schema: root, Example: text})
+	}
+	return media
+}
+
+// newSchema lays out a schema. Properties are listed by name, required
+// ones first.
+func newSchema(schema types.Schema, refs map[string]string, name string, required bool) *Schema {
+	view := &Schema{
+		Name:     name,
+		Type:     typeLabel(schema),
+		Required: required,
+		Nullable: schema.Nullable,
+		Enum:     schema.Enum,
+		Example:  exampleText(schema.Example),
+	}
+	if schema.Ref != "" {
+		view.Ref = refSlug(refs, schema.Ref)
+		return view
+	}
+	view.Constraints = constraints(schema)
+
+	isRequired := map[string]bool{}
+	for _, name := range schema.Required {
+		isRequired[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		if isRequired[names[i]] != isRequired[names[j]] {
+			return isRequired[names[i]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		view.Properties = append(view.Properties, newSchema(schema.Properties[name], refs, name, isRequired[name]))
+	}
+	if schema.Items != nil && schema.Items.Ref == "" && (len(schema.Items.Properties) > 0 || schema.Items.Items != nil) {
+		view.Items = newSchema(*schema.Items, refs, "items", false)
+	}
+	return view
+}
+
+// typeLabel names a schema's type, e.g. "string (email)", "User" or
+// "array of integer".
+func typeLabel(schema types.Schema) string {
+	if schema.Ref != "" {
+		return refName(schema.Ref)
+	}
+	label := schema.Type
+	if label == "" {
+		label = "any"
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		label = "array of " + typeLabel(*schema.Items)
+	}
+	if schema.Format != "" {
+		label += " (" + schema.Format + ")"
+	}
+	return label
+}
+
+func constraints(schema types.Schema) []string {
+	var out []string
+	if schema.Minimum != nil {
+		out = append(out, "≥ "+formatFloat(*schema.Minimum))
+	}
+	if schema.Maximum != nil {
+		out = append(out, "≤ "+formatFloat(*schema.Maximum))
+	}
+	if schema.MinLength != nil {
+		out = append(out, "min length "+strconv.Itoa(*schema.MinLength))
+	}
+	if schema.MaxLength != nil {
+		out = append(out, "max length "+strconv.Itoa(*schema.MaxLength))
+	}
+	if schema.MinItems != nil {
+		out = append(out, "min items "+strconv.Itoa(*schema.MinItems))
+	}
+	if schema.MaxItems != nil {
+		out = append(out, "max items "+strconv.Itoa(*schema.MaxItems))
+	}
+	if schema.Pattern != "" {
+		out = append(out, "pattern "+schema.Pattern)
+	}
+	return out
+}
+
+func schemeKind(scheme types.SecurityScheme) string {
+	switch scheme.Type {
+	case "http":
+		return "HTTP " + scheme.Scheme
+	case "apiKey":
+		return "API key in " + scheme.In + " " + scheme.Name
+	}
+	return scheme.Type
+}
+
+// searchIndex lists the pages the search box finds.
+func (s *Site) searchIndex() []SearchEntry {
+	var entries []SearchEntry
+	for _, op := range s.Operations {
+		entries = append(entries, SearchEntry{
+			Title: op.Method + " " + op.Path,
+			Kind:  "operation",
+			Text:  strings.Join([]string{op.Summary, op.Description, strings.Join(op.Tags, " ")}, " "),
+			URL:   "op-" + op.Slug + ".html",
+		})
+	}
+	for _, tag := range s.Tags {
+		entries = append(entries, SearchEntry{Title: tag.Name, Kind: "tag", Text: tag.Description, URL: "tag-" + tag.Slug + ".html"})
+	}
+	for _, schema := range s.Schemas {
+		var fields []string
+		for _, prop := range schema.Schema.Properties {
+			fields = append(fields, prop.Name)
+		}
+		entries = append(entries, SearchEntry{Title: schema.Name, Kind: "schema", Text: strings.Join(fields, " "), URL: "schema-" + schema.Slug + ".html"})
+	}
+	return entries
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug turns a name into a file name: "GET /users/{id}" becomes
+// "get-users-id".
+func slug(name string) string {
+	s := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if s == "" {
+		return "index"
+	}
+	return s
+}
+
+// slugs hands out the slugs of one kind of page. Names that slug alike,
+// such as "User" and "user" or "GET /users/{id}" and "GET /users/id", get
+// numbered slugs in the order they are asked for.
+type slugs map[string]bool
+
+func (s slugs) unique(name string) string {
+	base := slug(name)
+	candidate := base
+	for i := 2; s[candidate]; i++ {
+		candidate = base + "-" + strconv.Itoa(i)
+	}
+	s[candidate] = true
+	return candidate
+}
+
+// refSlug is the slug of the page of the schema ref points to.
+func refSlug(refs map[string]string, ref string) string {
+	if s, ok := refs[refName(ref)]; ok {
+		return s
+	}
+	return slug(refName(ref))
+}
+
+func exampleText(example interface{}) string {
+	switch v := example.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,206 @@
+// Package site renders a spec as documentation that is published without
+// the server: a static HTML site, or a single Markdown file.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/yourusername/atomicdocs/internal/openapi"
+)
+
+// The default templates. A directory given as Options.Templates may
+// replace any of them by holding a file of the same name.
+//
+//go:embed templates
+var defaults embed.FS
+
+// Options customise the rendering.
+type Options struct {
+	// Templates is a directory of templates replacing the defaults.
+	Templates string
+}
+
+// page is what an HTML page template is executed with. Only the field of
+// the page's kind is set.
+type page struct {
+	Site      *Site
+	Title     string
+	Tag       *Tag
+	Operation *Operation
+	Schema    *SchemaPage
+}
+
+// Field is a property of a schema at any depth, for flat listings such as
+// Markdown tables: Path is e.g. "address.city" or "items[].id".
+type Field struct {
+	Path string
+	*Schema
+}
+
+// Fields flattens the schema's properties and array items.
+func (s *Schema) Fields() []Field {
+	var fields []Field
+	var walk func(prefix string, schema *Schema)
+	walk = func(prefix string, schema *Schema) {
+		for _, prop := range schema.Properties {
+			fields = append(fields, Field{Path: prefix + prop.Name, Schema: prop})
+			walk(prefix+prop.Name+".", prop)
+			if prop.Items != nil {
+				walk(prefix+prop.Name+"[].", prop.Items)
+			}
+		}
+		if schema.Items != nil && prefix == "" {
+			walk("[].", schema.Items)
+		}
+	}
+	walk("", s)
+	return fields
+}
+
+// Build writes the static site for a spec into dir: index.html, a page
+// per tag, operation and component schema, the stylesheet and the search
+// script with its index. Links are relative, so the site can be served
+// from any path or opened from disk.
+func Build(spec *openapi.Spec, dir string, opts Options) error {
+	s := New(spec)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	render := func(file, tmpl string, p page) error {
+		t, err := opts.html(tmpl)
+		if err != nil {
+			return err
+		}
+		p.Site = s
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, "layout", p); err != nil {
+			return fmt.Errorf("failed to render %s: %w", file, err)
+		}
+		return os.WriteFile(filepath.Join(dir, file), buf.Bytes(), 0o644)
+	}
+
+	if err := render("index.html", "index.html", page{Title: s.Title}); err != nil {
+		return err
+	}
+	for _, tag := range s.Tags {
+		if err := render("tag-"+tag.Slug+".html", "tag.html", page{Title: tag.Name, Tag: tag}); err != nil {
+			return err
+		}
+	}
+	for _, op := range s.Operations {
+		if err := render("op-"+op.Slug+".html", "operation.html", page{Title: op.Title(), Operation: op}); err != nil {
+			return err
+		}
+	}
+	for _, schema := range s.Schemas {
+		if err := render("schema-"+schema.Slug+".html", "schema.html", page{Title: schema.Name, Schema: schema}); err != nil {
+			return err
+		}
+	}
+
+	for _, asset := range []string{"style.css", "search.js"} {
+		data, err := opts.read(asset)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, asset), data, 0o644); err != nil {
+			return err
+		}
+	}
+	// The index is a script rather than JSON so search works from file://.
+	index, err := json.Marshal(s.searchIndex())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "search-index.js"), []byte("window.ATOMICDOCS_SEARCH = "+string(index)+";\n"), 0o644)
+}
+
+// Markdown writes the docs for a spec as a single Markdown file, rendered
+// from docs.md.
+func Markdown(spec *openapi.Spec, w io.Writer, opts Options) error {
+	src, err := opts.read("docs.md")
+	if err != nil {
+		return err
+	}
+	t, err := texttemplate.New("docs.md").Funcs(texttemplate.FuncMap(funcs)).Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("failed to parse docs.md: %w", err)
+	}
+	return t.Execute(w, New(spec))
+}
+
+// html parses the layout with a page template.
+func (o Options) html(name string) (*htmltemplate.Template, error) {
+	t := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(funcs))
+	for _, file := range []string{"layout.html", name} {
+		src, err := o.read(file)
+		if err != nil {
+			return nil, err
+		}
+		if t, err = t.Parse(string(src)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+	return t, nil
+}
+
+// read returns a template or asset, from the override directory when it
+// has one of that name.
+func (o Options) read(name string) ([]byte, error) {
+	if o.Templates != "" {
+		data, err := os.ReadFile(filepath.Join(o.Templates, name))
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return defaults.ReadFile("templates/" + name)
+}
+
+var funcs = map[string]interface{}{
+	"lower": strings.ToLower,
+	// notes sums up a schema's constraints and allowed values.
+	"notes": func(s *Schema) string {
+		var notes []string
+		if s.Nullable {
+			notes = append(notes, "nullable")
+		}
+		notes = append(notes, s.Constraints...)
+		if len(s.Enum) > 0 {
+			notes = append(notes, "one of "+strings.Join(s.Enum, ", "))
+		}
+		return strings.Join(notes, "; ")
+	},
+	"join": strings.Join,
+	// cell makes text safe for a Markdown table cell.
+	"cell": func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(s)
+	},
+	// anchor is the id GitHub gives a Markdown heading.
+	"anchor": func(s string) string {
+		var b strings.Builder
+		for _, r := range strings.ToLower(s) {
+			switch {
+			case r == ' ':
+				b.WriteRune('-')
+			case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	},
+}
@@ -0,0 +1,55 @@
+// Searches the index in search-index.js as you type.
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  var index = window.ATOMICDOCS_SEARCH || [];
+  if (!input || !results) return;
+
+  function search(query) {
+    var words = query.toLowerCase().split(/\s+/).filter(Boolean);
+    if (!words.length) return [];
+    return index
+      .map(function (entry) {
+        var title = entry.title.toLowerCase();
+        var text = (entry.title + " " + entry.text).toLowerCase();
+        var score = 0;
+        for (var i = 0; i < words.length; i++) {
+          if (text.indexOf(words[i]) < 0) return null;
+          score += title.indexOf(words[i]) >= 0 ? 2 : 1;
+        }
+        return { entry: entry, score: score };
+      })
+      .filter(Boolean)
+      .sort(function (a, b) { return b.score - a.score; })
+      .slice(0, 20)
+      .map(function (match) { return match.entry; });
+  }
+
+  function render(matches) {
+    results.innerHTML = "";
+    matches.forEach(function (entry, i) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = entry.url;
+      a.textContent = entry.title;
+      if (i === 0) a.className = "active";
+      var kind = document.createElement("span");
+      kind.className = "kind";
+      kind.textContent = entry.kind;
+      a.appendChild(kind);
+      li.appendChild(a);
+      results.appendChild(li);
+    });
+    results.hidden = matches.length === 0;
+  }
+
+  input.addEventListener("input", function () { render(search(input.value)); });
+  input.addEventListener("keydown", function (e) {
+    var first = results.querySelector("a");
+    if (e.key === "Enter" && first) window.location.href = first.href;
+    if (e.key === "Escape") { input.value = ""; render([]); }
+  });
+  document.addEventListener("click", function (e) {
+    if (!results.contains(e.target) && e.target !== input) results.hidden = true;
+  });
+})();
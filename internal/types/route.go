@@ -1,9 +1,24 @@
 package types
 
+import (
+	"time"
+
+	"github.com/yourusername/atomicdocs/schema"
+)
+
+// The data descriptions are shared with the framework adapters.
+type (
+	Schema          = schema.Schema
+	Parameter       = schema.Parameter
+	RequestBody     = schema.RequestBody
+	MediaTypeObject = schema.MediaTypeObject
+)
+
 type RouteInfo struct {
 	Method      string              `json:"method"`
 	Path        string              `json:"path"`
 	Handler     string              `json:"handler"`
+	OperationID string              `json:"operationId,omitempty"`
 	FilePath    string              `json:"filePath,omitempty"`
 	Imports     []Import            `json:"imports,omitempty"`
 	Summary     string              `json:"summary,omitempty"`
@@ -13,6 +28,16 @@ type RouteInfo struct {
 	RequestBody *RequestBody        `json:"requestBody,omitempty"`
 	Responses   map[string]Response `json:"responses,omitempty"`
 	Security    []SecurityRequirement `json:"security,omitempty"`
+	Provenance  *Provenance         `json:"provenance,omitempty"`
+}
+
+// Provenance records where a route's schemas came from when they were not
+// written by hand, e.g. learned from observed traffic.
+type Provenance struct {
+	Source    string    `json:"source"`
+	Samples   int       `json:"samples,omitempty"`
+	FirstSeen time.Time `json:"firstSeen,omitempty"`
+	LastSeen  time.Time `json:"lastSeen,omitempty"`
 }
 
 type Import struct {
@@ -20,26 +45,6 @@ type Import struct {
 	From string `json:"from"`
 }
 
-type Parameter struct {
-	Name        string `json:"name"`
-	In          string `json:"in"` // path, query, header, cookie
-	Required    bool   `json:"required,omitempty"`
-	Schema      Schema `json:"schema"`
-	Description string `json:"description,omitempty"`
-	Example     interface{} `json:"example,omitempty"`
-}
-
-type RequestBody struct {
-	Required    bool                       `json:"required,omitempty"`
-	Description string                     `json:"description,omitempty"`
-	Content     map[string]MediaTypeObject `json:"content"`
-}
-
-type MediaTypeObject struct {
-	Schema  Schema      `json:"schema"`
-	Example interface{} `json:"example,omitempty"`
-}
-
 type Response struct {
 	Description string                     `json:"description"`
 	Content     map[string]MediaTypeObject `json:"content,omitempty"`
@@ -51,17 +56,6 @@ type HeaderObject struct {
 	Schema      Schema `json:"schema"`
 }
 
-type Schema struct {
-	Type       string            `json:"type,omitempty"`
-	Format     string            `json:"format,omitempty"`
-	Properties map[string]Schema `json:"properties,omitempty"`
-	Items      *Schema           `json:"items,omitempty"`
-	Example    interface{}       `json:"example,omitempty"`
-	Required   []string          `json:"required,omitempty"`
-	Enum       []string          `json:"enum,omitempty"`
-	Ref        string            `json:"$ref,omitempty"`
-}
-
 type SecurityRequirement map[string][]string
 
 type SecurityScheme struct {
@@ -36,6 +36,43 @@ interface HonoApp {
   }>;
 }
 
+interface AtomicDocsInfo {
+  title: string;
+  version: string;
+  description?: string;
+  termsOfService?: string;
+  contact?: { name?: string; url?: string; email?: string };
+  license?: { name: string; url?: string };
+}
+
+interface AtomicDocsServer {
+  url: string;
+  description?: string;
+  variables?: Record<string, { default: string; enum?: string[]; description?: string }>;
+}
+
+interface AtomicDocsTag {
+  name: string;
+  description?: string;
+  externalDocs?: { url: string; description?: string };
+}
+
+/**
+ * Options sent along with the routes. Metadata left out falls back to the
+ * AtomicDocs server defaults.
+ */
+interface AtomicDocsOptions {
+  info?: AtomicDocsInfo;
+  servers?: AtomicDocsServer[];
+  tags?: AtomicDocsTag[];
+  /** Commit the registration was made from, recorded in the version history. */
+  gitSha?: string;
+  /** Registration token; defaults to the ATOMICDOCS_TOKEN environment variable. */
+  token?: string;
+  /** Secret used to sign the payload; defaults to ATOMICDOCS_SECRET. */
+  secret?: string;
+}
+
 /**
  * AtomicDocs middleware for Express.js and Hono frameworks.
  * Auto-detects the framework and returns the appropriate middleware.
@@ -66,7 +103,7 @@ interface HonoApp {
  * app.use('*', atomicdocs(app, 3000));
  */
 declare function atomicdocs(): RequestHandler;
-declare function atomicdocs(app: HonoApp, port: number): MiddlewareHandler;
+declare function atomicdocs(app: HonoApp, port: number, options?: AtomicDocsOptions): MiddlewareHandler;
 
 declare namespace atomicdocs {
   /**
@@ -77,6 +114,7 @@ declare namespace atomicdocs {
    * 
    * @param app - Express application instance
    * @param port - Port number the server is running on
+   * @param options - Optional title, version, servers and tags for the spec
    * 
    * @example
    * import express from 'express';
@@ -94,7 +132,9 @@ declare namespace atomicdocs {
    *   atomicdocs.register(app, PORT);
    * });
    */
-  function register(app: ExpressApp | any, port: number): void;
+  function register(app: ExpressApp | any, port: number, options?: AtomicDocsOptions): void;
+
+  type Options = AtomicDocsOptions;
 }
 
 export = atomicdocs;
@@ -1,9 +1,12 @@
 const { spawn } = require('child_process');
+const crypto = require('crypto');
 const http = require('http');
 const path = require('path');
 
 let goServer = null;
 let serverReady = false;
+let readyCallbacks = [];
+let polling = false;
 
 function getBinaryName() {
   const platform = process.platform;
@@ -62,7 +65,42 @@ function startGoServer() {
     serverReady = false;
   });
   
-  setTimeout(() => { serverReady = true; }, 500);
+  waitForReady();
+}
+
+// Poll /readyz until the server answers, then run whatever was waiting on it.
+function waitForReady(attempt = 0) {
+  if (polling && attempt === 0) return;
+  polling = true;
+  
+  const retry = () => {
+    if (attempt >= 100) {
+      polling = false;
+      console.error('✗ AtomicDocs: Server did not become ready');
+      return;
+    }
+    setTimeout(() => waitForReady(attempt + 1), 100);
+  };
+  
+  http.get({ hostname: 'localhost', port: 6174, path: '/readyz' }, (res) => {
+    res.resume();
+    if (res.statusCode !== 200) {
+      retry();
+      return;
+    }
+    polling = false;
+    serverReady = true;
+    readyCallbacks.splice(0).forEach(cb => cb());
+  }).on('error', retry);
+}
+
+function whenReady(cb) {
+  if (serverReady) {
+    cb();
+    return;
+  }
+  readyCallbacks.push(cb);
+  waitForReady();
 }
 
 function isHono(app) {
@@ -181,18 +219,50 @@ function extractHonoRoutes(app) {
   })).filter(r => !r.path.startsWith('/docs'));
 }
 
-function registerRoutes(routes, port) {
-  const data = JSON.stringify({ routes, port });
+function registerRoutes(routes, port, options = {}) {
+  const { info, servers, tags, gitSha } = options;
+  const data = JSON.stringify({ routes, port, info, servers, tags, gitSha });
+  
+  const headers = {
+    'Content-Type': 'application/json',
+    'Content-Length': Buffer.byteLength(data)
+  };
+  
+  const token = options.token || process.env.ATOMICDOCS_TOKEN;
+  if (token) {
+    headers['Authorization'] = `Bearer ${token}`;
+  }
+  
+  const secret = options.secret || process.env.ATOMICDOCS_SECRET;
+  if (secret) {
+    const timestamp = Math.floor(Date.now() / 1000).toString();
+    const signature = crypto.createHmac('sha256', secret).update(`${timestamp}.${data}`).digest('hex');
+    headers['X-AtomicDocs-Timestamp'] = timestamp;
+    headers['X-AtomicDocs-Signature'] = `sha256=${signature}`;
+  }
   
   const req = http.request({
     hostname: 'localhost',
     port: 6174,
-    path: '/api/register',
+    path: '/api/v1/register',
     method: 'POST',
-    headers: { 
-      'Content-Type': 'application/json',
-      'Content-Length': Buffer.byteLength(data)
-    }
+    headers
+  }, (res) => {
+    let body = '';
+    res.on('data', chunk => body += chunk);
+    res.on('end', () => {
+      if (res.statusCode !== 200) {
+        console.error(`✗ AtomicDocs: Registration rejected (${res.statusCode}): ${body.trim()}`);
+        return;
+      }
+      let result = {};
+      try {
+        result = JSON.parse(body);
+      } catch (err) {
+        // Older servers reply with a status only.
+      }
+      printSummary(result, routes.length);
+    });
   });
   
   req.on('error', (err) => {
@@ -203,18 +273,64 @@ function registerRoutes(routes, port) {
   req.end();
 }
 
+// The warnings printed after a registration; the rest are counted.
+const MAX_LISTED = 10;
+
+// printSummary reports how the server documented the routes: which schemas
+// it resolved and parsed, what fell back to defaults, and what went wrong.
+function printSummary(result, count) {
+  const summary = result.summary || {};
+  const version = result.version ? ` (version ${result.version})` : '';
+  console.log(`✓ AtomicDocs: Registered ${count} routes${version}`);
+  
+  if (summary.imports > 0) {
+    const libraries = Object.keys(summary.libraries || {}).sort()
+      .map(library => `${library}: ${summary.libraries[library]}`);
+    const parsed = libraries.length > 0 ? ` (${libraries.join(', ')})` : '';
+    console.log(`  Schemas: ${summary.resolved} of ${summary.imports} imports resolved, ${summary.parsed} parsed${parsed}`);
+  }
+  if (summary.defaultResponses > 0) {
+    console.log(`  ${summary.defaultResponses} routes use the default responses`);
+  }
+  if (summary.missingBodies > 0) {
+    console.log(`  ${summary.missingBodies} routes document no request body`);
+  }
+  
+  const lines = [];
+  for (const route of result.routes || []) {
+    for (const warning of route.warnings || []) {
+      lines.push(`${route.method} ${route.path}: ${warning}`);
+    }
+  }
+  for (const problem of result.warnings || []) {
+    lines.push(`spec: ${problem.message} (at ${problem.pointer})`);
+  }
+  if (lines.length > 0) {
+    console.warn(`⚠ AtomicDocs: ${lines.length} warnings`);
+    lines.slice(0, MAX_LISTED).forEach(line => console.warn(`    ${line}`));
+    if (lines.length > MAX_LISTED) {
+      console.warn(`    … and ${lines.length - MAX_LISTED} more`);
+    }
+  }
+}
+
 // Express middleware
 function expressMiddleware() {
   startGoServer();
   
   return function(req, res, next) {
     if (req.path === '/docs' || req.path === '/docs/json') {
+      const headers = { 'X-App-Port': req.app.get('port') || req.socket.localPort };
+      if (req.headers.authorization) {
+        headers['Authorization'] = req.headers.authorization;
+      }
+      
       const options = {
         hostname: 'localhost',
         port: 6174,
         path: req.path,
         method: 'GET',
-        headers: { 'X-App-Port': req.app.get('port') || req.socket.localPort }
+        headers
       };
       
       http.get(options, (goRes) => {
@@ -231,31 +347,41 @@ function expressMiddleware() {
 }
 
 // Hono middleware
-function honoMiddleware(app, port) {
+function honoMiddleware(app, port, options) {
   startGoServer();
   
-  setTimeout(() => {
-    if (!serverReady) return;
+  // Routes are declared after the middleware, so collect them once the
+  // server is ready and the current tick has finished.
+  whenReady(() => setImmediate(() => {
     const routes = extractHonoRoutes(app);
-    registerRoutes(routes, port);
-  }, 1000);
+    registerRoutes(routes, port, options);
+  }));
   
   return async (c, next) => {
     if (c.req.path === '/docs' || c.req.path === '/docs/json') {
+      const headers = { 'X-App-Port': port.toString() };
+      const authorization = c.req.header('Authorization');
+      if (authorization) {
+        headers['Authorization'] = authorization;
+      }
+      
       return new Promise((resolve) => {
         http.get({
           hostname: 'localhost',
           port: 6174,
           path: c.req.path,
-          headers: { 'X-App-Port': port.toString() }
+          headers
         }, (res) => {
           let body = '';
           res.on('data', chunk => body += chunk);
           res.on('end', () => {
-            const contentType = res.headers['content-type'] || 'text/html';
+            const responseHeaders = { 'Content-Type': res.headers['content-type'] || 'text/html' };
+            if (res.headers['www-authenticate']) {
+              responseHeaders['WWW-Authenticate'] = res.headers['www-authenticate'];
+            }
             resolve(new Response(body, {
               status: res.statusCode,
-              headers: { 'Content-Type': contentType }
+              headers: responseHeaders
             }));
           });
         }).on('error', () => {
@@ -269,28 +395,26 @@ function honoMiddleware(app, port) {
 }
 
 // Auto-detect framework
-module.exports = function(app, port) {
+module.exports = function(app, port, options) {
   if (isHono(app)) {
-    return honoMiddleware(app, port);
+    return honoMiddleware(app, port, options);
   }
   return expressMiddleware();
 };
 
 // Express manual registration
-module.exports.register = function(app, port) {
+module.exports.register = function(app, port, options) {
   if (!serverReady) {
-    setTimeout(() => module.exports.register(app, port), 100);
+    whenReady(() => module.exports.register(app, port, options));
     return;
   }
   
   try {
     const routes = extractExpressRoutes(app);
-    if (routes.length > 0) {
-      console.log(`✓ AtomicDocs: Registered ${routes.length} routes`);
-    } else {
+    if (routes.length === 0) {
       console.warn('⚠ AtomicDocs: No routes found. Make sure routes are defined before calling register()');
     }
-    registerRoutes(routes, port);
+    registerRoutes(routes, port, options);
   } catch (err) {
     console.error('✗ AtomicDocs: Failed to extract routes:', err.message);
   }
@@ -0,0 +1,87 @@
+// Package schema holds the parts of an OpenAPI document that describe
+// request and response data. The server and the framework adapters share
+// them, so both validate against the same model.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Schema is an OpenAPI schema object, as far as AtomicDocs uses it.
+type Schema struct {
+	Type       string            `json:"type,omitempty"`
+	Format     string            `json:"format,omitempty"`
+	Properties map[string]Schema `json:"properties,omitempty"`
+	Items      *Schema           `json:"items,omitempty"`
+	Example    interface{}       `json:"example,omitempty"`
+	Required   []string          `json:"required,omitempty"`
+	Enum       []string          `json:"enum,omitempty"`
+	Ref        string            `json:"$ref,omitempty"`
+	Nullable   bool              `json:"nullable,omitempty"`
+	Minimum    *float64          `json:"minimum,omitempty"`
+	Maximum    *float64          `json:"maximum,omitempty"`
+	MinLength  *int              `json:"minLength,omitempty"`
+	MaxLength  *int              `json:"maxLength,omitempty"`
+	Pattern    string            `json:"pattern,omitempty"`
+	MinItems   *int              `json:"minItems,omitempty"`
+	MaxItems   *int              `json:"maxItems,omitempty"`
+}
+
+// Parameter is a path, query, header or cookie parameter of an operation.
+type Parameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      Schema      `json:"schema"`
+	Description string      `json:"description,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+}
+
+// RequestBody is the body an operation accepts, by media type.
+type RequestBody struct {
+	Required    bool                       `json:"required,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Content     map[string]MediaTypeObject `json:"content"`
+}
+
+// MediaTypeObject is the schema of one media type of a body.
+type MediaTypeObject struct {
+	Schema  Schema      `json:"schema"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+// UnmarshalJSON also accepts the type arrays of OpenAPI 3.1, such as
+// ["string", "null"]: the first type other than "null" becomes Type, and
+// "null" sets Nullable.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	var raw struct {
+		plain
+		Type interface{} `json:"type,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = Schema(raw.plain)
+	switch typ := raw.Type.(type) {
+	case nil:
+	case string:
+		s.Type = typ
+	case []interface{}:
+		for _, item := range typ {
+			name, ok := item.(string)
+			switch {
+			case !ok:
+				return fmt.Errorf("schema type %v is not a string", item)
+			case name == "null":
+				s.Nullable = true
+			case s.Type == "":
+				s.Type = name
+			}
+		}
+	default:
+		return fmt.Errorf("schema type %v is not a string or an array of strings", typ)
+	}
+	return nil
+}
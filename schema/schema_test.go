@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalType(t *testing.T) {
+	tests := []struct {
+		json string
+		want Schema
+	}{
+		{`{"type":"string"}`, Schema{Type: "string"}},
+		{`{"type":"integer","nullable":true}`, Schema{Type: "integer", Nullable: true}},
+		{`{"type":["string","null"]}`, Schema{Type: "string", Nullable: true}},
+		{`{"type":["null","integer"],"minimum":1}`, Schema{Type: "integer", Nullable: true, Minimum: func() *float64 { f := 1.0; return &f }()}},
+		{`{"type":"array","items":{"type":["number","null"]}}`, Schema{Type: "array", Items: &Schema{Type: "number", Nullable: true}}},
+		{`{"$ref":"#/components/schemas/User"}`, Schema{Ref: "#/components/schemas/User"}},
+	}
+	for _, tt := range tests {
+		var got Schema
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+			t.Errorf("Unmarshal(%s) = %v", tt.json, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.json, got, tt.want)
+		}
+	}
+
+	var s Schema
+	if err := json.Unmarshal([]byte(`{"type":1}`), &s); err == nil {
+		t.Error("Unmarshal of a numeric type succeeded, want an error")
+	}
+}
@@ -0,0 +1,103 @@
+package validate
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/atomicdocs/schema"
+)
+
+// Request is the part of an HTTP request that is checked against an
+// operation.
+type Request struct {
+	PathParams  map[string]string
+	Query       func(name string) []string
+	Header      func(name string) string
+	ContentType string
+	Body        []byte
+}
+
+// CheckRequest checks a request against the parameters and JSON request
+// body of an operation. It returns the errors found and the status to
+// report them with: 415 when a JSON body was sent as something else, 400
+// otherwise.
+func CheckRequest(params []schema.Parameter, body *schema.RequestBody, req Request, resolve Resolver) (int, []FieldError) {
+	var errs []FieldError
+
+	for _, param := range params {
+		var raw []string
+		switch param.In {
+		case "path":
+			if value, ok := req.PathParams[param.Name]; ok {
+				raw = []string{value}
+			}
+		case "query":
+			raw = req.Query(param.Name)
+		case "header":
+			if value := req.Header(param.Name); value != "" {
+				raw = []string{value}
+			}
+		default:
+			continue
+		}
+
+		v := &validator{in: param.In, name: param.Name, resolve: resolve}
+		switch {
+		case len(raw) == 0:
+			if param.Required {
+				v.fail("", "is required")
+			}
+		default:
+			value, ok := CoerceParam(param.Schema, raw)
+			if !ok {
+				v.fail("", "must be %s", describeType(param.Schema))
+			} else {
+				v.check(param.Schema, value, "")
+			}
+		}
+		errs = append(errs, v.errors...)
+	}
+
+	if body == nil {
+		return http.StatusBadRequest, errs
+	}
+	media, ok := body.Content["application/json"]
+	if !ok {
+		return http.StatusBadRequest, errs
+	}
+
+	v := &validator{in: "body", resolve: resolve}
+	if len(req.Body) == 0 {
+		if body.Required {
+			v.fail("", "is required")
+		}
+		return http.StatusBadRequest, append(errs, v.errors...)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.ContentType)
+	if !IsJSON(mediaType) {
+		v.fail("", "must be sent as application/json")
+		return http.StatusUnsupportedMediaType, append(errs, v.errors...)
+	}
+
+	value, err := DecodeJSON(req.Body)
+	if err != nil {
+		v.fail("", "is not valid JSON: %v", err)
+	} else {
+		v.check(media.Schema, value, "")
+	}
+	return http.StatusBadRequest, append(errs, v.errors...)
+}
+
+// IsJSON reports whether a media type, without parameters, holds JSON.
+func IsJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func describeType(s schema.Schema) string {
+	if s.Type == "array" && s.Items != nil {
+		return "a list of " + s.Items.Type + " values"
+	}
+	return withArticle(s.Type)
+}
@@ -0,0 +1,299 @@
+// Package validate checks values and requests against documented schemas.
+// The server's mock and the Fiber adapter both use it.
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/atomicdocs/schema"
+)
+
+// FieldError describes one place where a value breaks its documented
+// schema. Pointer is a JSON pointer into a body; Name names a parameter.
+type FieldError struct {
+	In      string `json:"in"`
+	Name    string `json:"name,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+// Resolver follows schema references. A nil Resolver leaves them as is.
+type Resolver func(schema.Schema) schema.Schema
+
+// validator collects the errors found while checking one message.
+type validator struct {
+	in      string
+	name    string
+	resolve Resolver
+	errors  []FieldError
+}
+
+// Value checks a decoded JSON value against a schema. Numbers must be
+// json.Number, as DecodeJSON returns them.
+func Value(in string, s schema.Schema, value interface{}, resolve Resolver) []FieldError {
+	v := &validator{in: in, resolve: resolve}
+	v.check(s, value, "")
+	return v.errors
+}
+
+func (v *validator) fail(pointer, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{
+		In:      v.in,
+		Name:    v.name,
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// DecodeJSON decodes a body keeping numbers as json.Number, so integers
+// can be told apart from decimals.
+func DecodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+func (v *validator) check(s schema.Schema, value interface{}, pointer string) {
+	if s.Ref != "" && v.resolve != nil {
+		s = v.resolve(s)
+	}
+	if value == nil {
+		if !s.Nullable && s.Type != "" {
+			v.fail(pointer, "must not be null")
+		}
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(pointer, "must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				v.fail(pointer+"/"+escapePointer(name), "is required")
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if field, ok := obj[name]; ok {
+				v.check(s.Properties[name], field, pointer+"/"+escapePointer(name))
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.fail(pointer, "must be an array")
+			return
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			v.fail(pointer, "must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			v.fail(pointer, "must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				v.check(*s.Items, item, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.fail(pointer, "must be a string")
+			return
+		}
+		length := utf8.RuneCountInString(str)
+		if s.MinLength != nil && length < *s.MinLength {
+			v.fail(pointer, "must be at least %d characters long", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			v.fail(pointer, "must be at most %d characters long", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := compilePattern(s.Pattern); err == nil && !re.MatchString(str) {
+				v.fail(pointer, "must match the pattern %s", s.Pattern)
+			}
+		}
+		if s.Format != "" && !ValidFormat(s.Format, str) {
+			v.fail(pointer, "must be a valid %s", s.Format)
+		}
+		v.checkEnum(s, str, pointer)
+
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			v.fail(pointer, "must be %s", withArticle(s.Type))
+			return
+		}
+		f, err := n.Float64()
+		if err != nil {
+			v.fail(pointer, "must be %s", withArticle(s.Type))
+			return
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			v.fail(pointer, "must be an integer")
+			return
+		}
+		if s.Format == "int32" && (f < math.MinInt32 || f > math.MaxInt32) {
+			v.fail(pointer, "must be a 32-bit integer")
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			v.fail(pointer, "must be at least %s", formatNumber(*s.Minimum))
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			v.fail(pointer, "must be at most %s", formatNumber(*s.Maximum))
+		}
+		v.checkEnum(s, formatNumber(f), pointer)
+
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			v.fail(pointer, "must be a boolean")
+			return
+		}
+		v.checkEnum(s, strconv.FormatBool(b), pointer)
+	}
+}
+
+func (v *validator) checkEnum(s schema.Schema, value, pointer string) {
+	if len(s.Enum) == 0 {
+		return
+	}
+	for _, allowed := range s.Enum {
+		if allowed == value {
+			return
+		}
+	}
+	v.fail(pointer, "must be one of %s", strings.Join(s.Enum, ", "))
+}
+
+// CoerceParam turns the raw values of a path, query or header parameter
+// into the JSON value its schema describes, so it can be checked like a
+// body. Arrays accept repeated values or a comma-separated list.
+func CoerceParam(s schema.Schema, raw []string) (interface{}, bool) {
+	if s.Type == "array" {
+		if len(raw) == 1 {
+			raw = strings.Split(raw[0], ",")
+		}
+		items := make([]interface{}, len(raw))
+		itemSchema := schema.Schema{Type: "string"}
+		if s.Items != nil {
+			itemSchema = *s.Items
+		}
+		for i, value := range raw {
+			item, ok := CoerceParam(itemSchema, []string{value})
+			if !ok {
+				return nil, false
+			}
+			items[i] = item
+		}
+		return items, true
+	}
+
+	value := raw[0]
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(value), true
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, false
+		}
+		return b, true
+	default:
+		return value, true
+	}
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	patterns    sync.Map
+)
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// ValidFormat checks the string formats OpenAPI defines or commonly uses.
+// Unknown formats are accepted.
+func ValidFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "uri", "url":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+func withArticle(typ string) string {
+	if typ == "integer" || typ == "array" || typ == "object" {
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/atomicdocs/schema"
+)
+
+func TestValue(t *testing.T) {
+	min := 1.0
+	user := schema.Schema{
+		Type:     "object",
+		Required: []string{"email"},
+		Properties: map[string]schema.Schema{
+			"email": {Type: "string", Format: "email"},
+			"age":   {Type: "integer", Minimum: &min},
+			"role":  {Type: "string", Enum: []string{"admin", "member"}},
+			"tags":  {Type: "array", Items: &schema.Schema{Type: "string"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{"valid", `{"email":"a@example.com","age":3,"role":"admin","tags":["x"]}`, nil},
+		{"missing required", `{}`, []FieldError{{In: "body", Pointer: "/email", Message: "is required"}}},
+		{"bad format", `{"email":"nope"}`, []FieldError{{In: "body", Pointer: "/email", Message: "must be a valid email"}}},
+		{"decimal integer", `{"email":"a@example.com","age":1.5}`, []FieldError{{In: "body", Pointer: "/age", Message: "must be an integer"}}},
+		{"below minimum", `{"email":"a@example.com","age":0}`, []FieldError{{In: "body", Pointer: "/age", Message: "must be at least 1"}}},
+		{"not in enum", `{"email":"a@example.com","role":"root"}`, []FieldError{{In: "body", Pointer: "/role", Message: "must be one of admin, member"}}},
+		{"wrong item type", `{"email":"a@example.com","tags":[1]}`, []FieldError{{In: "body", Pointer: "/tags/0", Message: "must be a string"}}},
+		{"wrong type", `[]`, []FieldError{{In: "body", Message: "must be an object"}}},
+	}
+	for _, tt := range tests {
+		value, err := DecodeJSON([]byte(tt.body))
+		if err != nil {
+			t.Fatalf("%s: DecodeJSON() = %v", tt.name, err)
+		}
+		if got := Value("body", user, value, nil); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Value() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValueResolvesReferences(t *testing.T) {
+	resolve := func(s schema.Schema) schema.Schema {
+		if s.Ref == "#/components/schemas/ID" {
+			return schema.Schema{Type: "string", Format: "uuid"}
+		}
+		return s
+	}
+	ref := schema.Schema{Ref: "#/components/schemas/ID"}
+
+	if errs := Value("body", ref, "not-a-uuid", resolve); len(errs) != 1 {
+		t.Errorf("Value() with a resolver = %+v, want one error", errs)
+	}
+	if errs := Value("body", ref, "not-a-uuid", nil); len(errs) != 0 {
+		t.Errorf("Value() without a resolver = %+v, want none", errs)
+	}
+}
+
+func TestCheckRequest(t *testing.T) {
+	params := []schema.Parameter{
+		{Name: "id", In: "path", Required: true, Schema: schema.Schema{Type: "integer"}},
+		{Name: "ids", In: "query", Schema: schema.Schema{Type: "array", Items: &schema.Schema{Type: "integer"}}},
+	}
+	body := &schema.RequestBody{
+		Required: true,
+		Content: map[string]schema.MediaTypeObject{
+			"application/json": {Schema: schema.Schema{Type: "object"}},
+		},
+	}
+	request := func(id, ids, contentType, payload string) Request {
+		return Request{
+			PathParams:  map[string]string{"id": id},
+			Query:       func(string) []string { return []string{ids} },
+			Header:      func(string) string { return "" },
+			ContentType: contentType,
+			Body:        []byte(payload),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		req    Request
+		status int
+		errs   int
+	}{
+		{"valid", request("1", "1,2", "application/json", `{}`), http.StatusBadRequest, 0},
+		{"problem json", request("1", "1", "application/merge-patch+json; charset=utf-8", `{}`), http.StatusBadRequest, 0},
+		{"bad parameters", request("x", "1,y", "application/json", `{}`), http.StatusBadRequest, 2},
+		{"missing body", request("1", "1", "", ""), http.StatusBadRequest, 1},
+		{"invalid JSON", request("1", "1", "application/json", `{`), http.StatusBadRequest, 1},
+		{"not JSON", request("1", "1", "text/plain", "hi"), http.StatusUnsupportedMediaType, 1},
+	}
+	for _, tt := range tests {
+		status, errs := CheckRequest(params, body, tt.req, nil)
+		if len(errs) != tt.errs || (tt.errs > 0 && status != tt.status) {
+			t.Errorf("%s: CheckRequest() = %d, %+v, want %d with %d errors", tt.name, status, errs, tt.status, tt.errs)
+		}
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	tests := []struct {
+		format, value string
+		want          bool
+	}{
+		{"date-time", "2024-01-02T03:04:05Z", true},
+		{"date-time", "2024-01-02", false},
+		{"date", "2024-01-02", true},
+		{"email", "a@example.com", true},
+		{"email", "Alice <a@example.com>", false},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uuid", "123e4567", false},
+		{"uri", "https://example.com/x", true},
+		{"uri", "/relative", false},
+		{"ipv4", "10.0.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "::1", true},
+		{"unknown", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := ValidFormat(tt.format, tt.value); got != tt.want {
+			t.Errorf("ValidFormat(%q, %q) = %v, want %v", tt.format, tt.value, got, tt.want)
+		}
+	}
+}